@@ -0,0 +1,172 @@
+package errless
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/mfatihercik/errless/internal/throw"
+)
+
+// ErrCircuitOpen is the error of the calls a Breaker rejects.
+var ErrCircuitOpen = errors.New("errless: circuit breaker is open")
+
+// BreakerState is the state of a Breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets every call through.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects every call with ErrCircuitOpen until its cooldown passes.
+	BreakerOpen
+	// BreakerHalfOpen lets a single trial call through, which closes or opens the breaker again.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// Breaker is a circuit breaker for the calls to a downstream service. It opens after
+// threshold failed calls in a row, rejects calls while it is open, and lets a trial call
+// through once its cooldown has passed. It uses the clock set by SetClock, and is safe for
+// concurrent use.
+//
+//	var payments = errless.NewBreaker(5, 30*time.Second, errless.IsNot(ErrDeclined))
+//
+//	receipt := errless.TryBreaker1(payments, func() (Receipt, error) {
+//		return client.Charge(order)
+//	}).ErrMessage("charge")
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	filters   []IfFunc
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	trial    bool // a trial call is running in the half-open state
+}
+
+// NewBreaker returns a closed breaker that opens after threshold failures in a row and stays
+// open for cooldown. An error counts as a failure if it matches one of the filters, or if
+// no filter is given. Other errors count as successes, as the service did respond.
+func NewBreaker(threshold int, cooldown time.Duration, filters ...IfFunc) *Breaker {
+	return &Breaker{threshold: max(threshold, 1), cooldown: cooldown, filters: filters}
+}
+
+// State returns the state of the breaker.
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerOpen && b.cooled() {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// Try calls fn unless the breaker is open, and checks its result like Try.
+// The error is ErrCircuitOpen if the call was rejected. An error thrown by fn is checked
+// like the error fn returns, but the registered handlers are not applied to it again.
+func (b *Breaker) Try(fn func() error) Params0 {
+	_, err, thrown := callBreaker(b, func() (struct{}, error) {
+		return struct{}{}, fn()
+	})
+	if thrown {
+		return Params0{err: err, thrown: true}
+	}
+	return Try(err)
+}
+
+// TryBreaker1 is Breaker.Try for a function that returns a value.
+func TryBreaker1[A any](b *Breaker, fn func() (A, error)) Params1[A] {
+	a, err, thrown := callBreaker(b, fn)
+	if thrown {
+		return Params1[A]{err: err, thrown: true}
+	}
+	return Try1(a, err)
+}
+
+// callBreaker calls fn unless the breaker is open, and reports whether its error was thrown.
+// A panic that was not raised by errless counts as a failure, and is re-panicked.
+func callBreaker[A any](b *Breaker, fn func() (A, error)) (a A, err error, thrown bool) {
+	trial, err := b.allow()
+	if err != nil {
+		return a, err, false
+	}
+	defer func() {
+		r := recover()
+		if r == nil {
+			b.record(b.failed(err), trial)
+			return
+		}
+		exp, ok := r.(throw.Exception)
+		if !ok {
+			b.record(true, trial)
+			panic(r)
+		}
+		err, thrown = exp.Err, true
+		b.record(b.failed(err), trial)
+	}()
+	a, err = fn()
+	return a, err, false
+}
+
+// allow returns ErrCircuitOpen if the call must be rejected, and whether the call is the
+// trial call of the half-open state.
+func (b *Breaker) allow() (trial bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case BreakerClosed:
+		return false, nil
+	case BreakerOpen:
+		if !b.cooled() {
+			return false, ErrCircuitOpen
+		}
+		b.state = BreakerHalfOpen
+	}
+	if b.trial {
+		return false, ErrCircuitOpen
+	}
+	b.trial = true
+	return true, nil
+}
+
+// failed reports whether err counts as a failure.
+func (b *Breaker) failed(err error) bool {
+	return err != nil && (len(b.filters) == 0 || applyNextStep(b.filters, err, false))
+}
+
+// record updates the state with the result of a call. The results of the calls started
+// before the breaker opened are ignored, except by the closed state.
+func (b *Breaker) record(failed bool, trial bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if trial {
+		b.trial = false
+	} else if b.state != BreakerClosed {
+		return
+	}
+	if !failed {
+		b.state, b.failures = BreakerClosed, 0
+		return
+	}
+	b.failures++
+	if trial || b.failures >= b.threshold {
+		b.state, b.openedAt = BreakerOpen, clock().Now()
+	}
+}
+
+func (b *Breaker) cooled() bool {
+	return !clock().Now().Before(b.openedAt.Add(b.cooldown))
+}
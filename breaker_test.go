@@ -0,0 +1,141 @@
+//go:build test
+
+package errless_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	e "github.com/mfatihercik/errless"
+	"github.com/mfatihercik/errless/errlesstest"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errUnavailable = errors.New("service unavailable")
+	errDeclined    = errors.New("card declined")
+)
+
+func call(b *e.Breaker, err error) error {
+	return errlesstest.CaptureThrow(func() {
+		e.TryBreaker1(b, func() (int, error) { return 1, err }).Err()
+	})
+}
+
+func TestBreaker(t *testing.T) {
+	t.Run("should open after the threshold", func(t *testing.T) {
+		errlesstest.UseFakeClock(t)
+		b := e.NewBreaker(2, time.Minute)
+		assert.ErrorIs(t, call(b, errUnavailable), errUnavailable)
+		assert.Equal(t, e.BreakerClosed, b.State())
+		assert.ErrorIs(t, call(b, errUnavailable), errUnavailable)
+		assert.Equal(t, e.BreakerOpen, b.State())
+
+		called := false
+		err := errlesstest.CaptureThrow(func() {
+			e.TryBreaker1(b, func() (int, error) { called = true; return 1, nil }).Err()
+		})
+		assert.ErrorIs(t, err, e.ErrCircuitOpen)
+		assert.False(t, called)
+	})
+	t.Run("should reset the failures after a success", func(t *testing.T) {
+		errlesstest.UseFakeClock(t)
+		b := e.NewBreaker(2, time.Minute)
+		call(b, errUnavailable)
+		call(b, nil)
+		call(b, errUnavailable)
+		assert.Equal(t, e.BreakerClosed, b.State())
+	})
+	t.Run("should close after a successful trial", func(t *testing.T) {
+		clock := errlesstest.UseFakeClock(t)
+		b := e.NewBreaker(1, time.Minute)
+		call(b, errUnavailable)
+		clock.Advance(59 * time.Second)
+		assert.ErrorIs(t, call(b, nil), e.ErrCircuitOpen)
+		clock.Advance(time.Second)
+		assert.Equal(t, e.BreakerHalfOpen, b.State())
+		assert.NoError(t, call(b, nil))
+		assert.Equal(t, e.BreakerClosed, b.State())
+	})
+	t.Run("should open again after a failed trial", func(t *testing.T) {
+		clock := errlesstest.UseFakeClock(t)
+		b := e.NewBreaker(3, time.Minute)
+		call(b, errUnavailable)
+		call(b, errUnavailable)
+		call(b, errUnavailable)
+		clock.Advance(time.Minute)
+		assert.ErrorIs(t, call(b, errUnavailable), errUnavailable)
+		assert.Equal(t, e.BreakerOpen, b.State())
+		assert.ErrorIs(t, call(b, nil), e.ErrCircuitOpen)
+	})
+	t.Run("should let a single trial through", func(t *testing.T) {
+		clock := errlesstest.UseFakeClock(t)
+		b := e.NewBreaker(1, time.Minute)
+		call(b, errUnavailable)
+		clock.Advance(time.Minute)
+		b.Try(func() error {
+			assert.ErrorIs(t, call(b, nil), e.ErrCircuitOpen)
+			return nil
+		}).Err()
+		assert.Equal(t, e.BreakerClosed, b.State())
+	})
+	t.Run("should count filtered errors only", func(t *testing.T) {
+		errlesstest.UseFakeClock(t)
+		b := e.NewBreaker(1, time.Minute, e.IsNot(errDeclined))
+		assert.ErrorIs(t, call(b, errDeclined), errDeclined)
+		assert.Equal(t, e.BreakerClosed, b.State())
+		call(b, errUnavailable)
+		assert.Equal(t, e.BreakerOpen, b.State())
+	})
+	t.Run("should count thrown errors", func(t *testing.T) {
+		errlesstest.UseFakeClock(t)
+		b := e.NewBreaker(1, time.Minute)
+		err := errlesstest.CaptureThrow(func() {
+			b.Try(func() error {
+				e.Throw(errUnavailable)
+				return nil
+			}).Err()
+		})
+		assert.ErrorIs(t, err, errUnavailable)
+		assert.Equal(t, e.BreakerOpen, b.State())
+	})
+	t.Run("should apply the default handlers once to thrown errors", func(t *testing.T) {
+		errlesstest.UseFakeClock(t)
+		errlesstest.DefaultHandlers(t, prefix("svc"))
+		b := e.NewBreaker(5, time.Minute)
+		err := errlesstest.CaptureThrow(func() {
+			e.TryBreaker1(b, func() (int, error) {
+				e.Throw(errUnavailable)
+				return 1, nil
+			}).ErrMessage("charge")
+		})
+		assert.EqualError(t, err, "charge - error: svc: service unavailable")
+	})
+	t.Run("should count other panics as failures and end the trial", func(t *testing.T) {
+		clock := errlesstest.UseFakeClock(t)
+		b := e.NewBreaker(1, time.Minute)
+		call(b, errUnavailable)
+		clock.Advance(time.Minute)
+		assert.PanicsWithValue(t, "boom", func() {
+			b.Try(func() error { panic("boom") })
+		})
+		assert.Equal(t, e.BreakerOpen, b.State())
+		clock.Advance(time.Minute)
+		assert.NoError(t, call(b, nil))
+		assert.Equal(t, e.BreakerClosed, b.State())
+	})
+	t.Run("should work with Fallback", func(t *testing.T) {
+		errlesstest.UseFakeClock(t)
+		b := e.NewBreaker(1, time.Minute)
+		call(b, errUnavailable)
+		v := e.TryBreaker1(b, func() (int, error) { return 1, nil }).IfIs(e.ErrCircuitOpen).Fallback(func(error) int { return -1 })
+		assert.Equal(t, -1, v)
+	})
+}
+
+func TestBreakerState(t *testing.T) {
+	assert.Equal(t, "closed", e.BreakerClosed.String())
+	assert.Equal(t, "open", e.BreakerOpen.String())
+	assert.Equal(t, "half-open", e.BreakerHalfOpen.String())
+}
@@ -0,0 +1,138 @@
+package errless
+
+// class is the classification an AsX handler puts on an error.
+type class int
+
+const (
+	classInternal class = iota
+	classRetryable
+)
+
+// classifiedError tags the error it wraps with a class. Its Error is the wrapped error's,
+// so the internal detail stays available for logs.
+type classifiedError struct {
+	err   error
+	class class
+}
+
+func (e *classifiedError) Error() string { return e.err.Error() }
+func (e *classifiedError) Unwrap() error { return e.err }
+
+// AsUserError returns a handler that marks the error as safe to show to users, with message as
+// the text shown to them. The error is a *PublicError.
+func AsUserError(message string) HandlerFunc {
+	return func(err error) error {
+		return &PublicError{Err: err, Message: message}
+	}
+}
+
+// AsInternal returns a handler that marks the error as internal, so it is not shown to users
+// even if an error it wraps is user facing.
+func AsInternal() HandlerFunc {
+	return func(err error) error {
+		return &classifiedError{err: err, class: classInternal}
+	}
+}
+
+// AsRetryable returns a handler that marks the error as retryable.
+func AsRetryable() HandlerFunc {
+	return func(err error) error {
+		return &classifiedError{err: err, class: classRetryable}
+	}
+}
+
+// IfRetryable reports whether err is retryable. It can be used with If.
+func IfRetryable(err error) bool {
+	return IsRetryable(err)
+}
+
+// IfUserFacing reports whether err is user facing. It can be used with If.
+func IfUserFacing(err error) bool {
+	return IsUserFacing(err)
+}
+
+// IsRetryable reports whether err was marked with AsRetryable, or an error in its chain
+// reports itself as temporary or as a timeout, like the errors of the net package.
+func IsRetryable(err error) bool {
+	retryable := false
+	walk(err, func(err error) bool {
+		if c, ok := err.(*classifiedError); ok && c.class == classRetryable {
+			retryable = true
+			return true
+		}
+		if t, ok := err.(interface{ Temporary() bool }); ok && t.Temporary() {
+			retryable = true
+			return true
+		}
+		if t, ok := err.(interface{ Timeout() bool }); ok && t.Timeout() {
+			retryable = true
+			return true
+		}
+		return false
+	})
+	return retryable
+}
+
+// IsTimeout reports whether an error in the chain of err reports itself as a timeout.
+func IsTimeout(err error) bool {
+	timeout := false
+	walk(err, func(err error) bool {
+		if t, ok := err.(interface{ Timeout() bool }); ok && t.Timeout() {
+			timeout = true
+		}
+		return timeout
+	})
+	return timeout
+}
+
+// IsUserFacing reports whether err is safe to show to users: the outermost public error or
+// AsInternal in its chain is a public error.
+func IsUserFacing(err error) bool {
+	_, ok := UserMessage(err)
+	return ok
+}
+
+// IsInternal reports whether err is not user facing.
+func IsInternal(err error) bool {
+	return !IsUserFacing(err)
+}
+
+// UserMessage returns the message of the outermost error with a PublicMessage() string method
+// in the chain of err, unless an AsInternal wraps it.
+func UserMessage(err error) (message string, ok bool) {
+	walk(err, func(err error) bool {
+		if c, classified := err.(*classifiedError); classified {
+			return c.class == classInternal
+		}
+		if p, public := err.(interface{ PublicMessage() string }); public {
+			message, ok = p.PublicMessage(), true
+			return true
+		}
+		return false
+	})
+	return message, ok
+}
+
+// walk calls visit for err and the errors it wraps, depth first, until visit returns true.
+// It follows both Unwrap() error and Unwrap() []error.
+func walk(err error, visit func(error) bool) bool {
+	for err != nil {
+		if visit(err) {
+			return true
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, e := range u.Unwrap() {
+				if walk(e, visit) {
+					return true
+				}
+			}
+			return false
+		default:
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+//go:build test
+
+package errless_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	e "github.com/mfatihercik/errless"
+	"github.com/mfatihercik/errless/errlesstest"
+	"github.com/stretchr/testify/assert"
+)
+
+var errDB = errors.New("pq: connection refused on 10.0.0.3")
+
+// netError behaves like the errors of the net package.
+type netError struct{ timeout, temporary bool }
+
+func (n netError) Error() string   { return "net error" }
+func (n netError) Timeout() bool   { return n.timeout }
+func (n netError) Temporary() bool { return n.temporary }
+
+// multiError wraps several errors, like the errors returned by errors.Join.
+type multiError []error
+
+func (m multiError) Error() string   { return "multiple errors" }
+func (m multiError) Unwrap() []error { return m }
+
+func TestClassification(t *testing.T) {
+	t.Run("should mark user facing errors", func(t *testing.T) {
+		err := e.AsUserError("account not found")(errDB)
+		assert.True(t, e.IsUserFacing(err))
+		assert.False(t, e.IsInternal(err))
+		msg, ok := e.UserMessage(fmt.Errorf("load: %w", err))
+		assert.True(t, ok)
+		assert.Equal(t, "account not found", msg)
+		assert.EqualError(t, err, errDB.Error())
+		assert.ErrorIs(t, err, errDB)
+	})
+	t.Run("should let the outermost classification win", func(t *testing.T) {
+		err := e.AsInternal()(e.AsUserError("account not found")(errDB))
+		assert.False(t, e.IsUserFacing(err))
+		assert.True(t, e.IsInternal(err))
+		err = e.AsUserError("try again")(e.AsRetryable()(err))
+		assert.True(t, e.IsUserFacing(err))
+		assert.True(t, e.IsRetryable(err))
+	})
+	t.Run("should treat unclassified errors as internal", func(t *testing.T) {
+		assert.True(t, e.IsInternal(errDB))
+		assert.False(t, e.IsRetryable(errDB))
+		assert.False(t, e.IsUserFacing(nil))
+	})
+	t.Run("should honor Temporary and Timeout", func(t *testing.T) {
+		assert.True(t, e.IsRetryable(fmt.Errorf("dial: %w", netError{temporary: true})))
+		assert.True(t, e.IsRetryable(netError{timeout: true}))
+		assert.True(t, e.IsTimeout(fmt.Errorf("dial: %w", netError{timeout: true})))
+		assert.False(t, e.IsRetryable(netError{}))
+		assert.False(t, e.IsTimeout(netError{temporary: true}))
+	})
+	t.Run("should walk joined errors", func(t *testing.T) {
+		err := multiError{errDB, e.AsUserError("busy")(netError{temporary: true})}
+		assert.True(t, e.IsRetryable(err))
+		assert.True(t, e.IsUserFacing(fmt.Errorf("save: %w", err)))
+	})
+}
+
+func TestClassificationPredicates(t *testing.T) {
+	t.Run("should throw retryable errors only", func(t *testing.T) {
+		errlesstest.AssertNoThrow(t, func() {
+			e.Try(errDB).If(e.IfRetryable).Err()
+		})
+		errlesstest.AssertThrows(t, func() {
+			e.Try(netError{timeout: true}).If(e.IfRetryable).Err()
+		})
+	})
+	t.Run("should throw user facing errors only", func(t *testing.T) {
+		errlesstest.AssertNoThrow(t, func() {
+			e.Try(errDB).If(e.IfUserFacing).Err()
+		})
+		err := errlesstest.CaptureThrow(func() {
+			e.Try(errDB).Err(e.AsUserError("account not found"))
+		})
+		assert.True(t, e.IfUserFacing(err))
+	})
+}
@@ -0,0 +1,45 @@
+package errless
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Clock tells the time to the errless functions that wait or measure time,
+// so tests can replace it with a fake clock.
+type Clock interface {
+	Now() time.Time
+	// After returns a channel that receives the time once d has passed.
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+type clockHolder struct {
+	clock Clock
+}
+
+var currentClock atomic.Pointer[clockHolder]
+
+// SetClock sets the clock of errless for the whole process. A nil c restores the system clock.
+// It returns a function that restores the previous clock.
+func SetClock(c Clock) (restore func()) {
+	var next *clockHolder
+	if c != nil {
+		next = &clockHolder{clock: c}
+	}
+	previous := currentClock.Swap(next)
+	return func() {
+		currentClock.Store(previous)
+	}
+}
+
+func clock() Clock {
+	if h := currentClock.Load(); h != nil {
+		return h.clock
+	}
+	return systemClock{}
+}
@@ -0,0 +1,764 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"github.com/mfatihercik/errless/internal/codemod"
+)
+
+// terminal is the ParamsN method that ends a call chain.
+type terminal int
+
+const (
+	terminalErr terminal = iota
+	terminalFallback
+	terminalOr
+)
+
+// handler is a HandlerFunc applied to the error; message is set for errless.Message and
+// errless.Wrap, and public for errless.Public.
+type handler struct {
+	expr    ast.Expr
+	message ast.Expr
+	public  ast.Expr
+}
+
+// filter is an IfFunc; kind is the errless predicate it was built with, or "" for other functions.
+type filter struct {
+	kind string
+	expr ast.Expr
+}
+
+// site is an errless call chain used as a statement.
+type site struct {
+	stmt     ast.Stmt
+	lhs      []ast.Expr // assigned values, nil for expression statements
+	tok      token.Token
+	call     ast.Expr // the call returning the values and the error
+	values   int
+	groups   [][]filter
+	handlers []handler
+	kind     terminal
+	fallback ast.Expr
+}
+
+// function is a function with a deferred errless.Handle or errless.HandleErr.
+type function struct {
+	typ     *ast.FuncType
+	body    *ast.BlockStmt
+	scope   *types.Scope
+	results []types.Object
+	errName string
+	handle  *ast.DeferStmt
+	onError ast.Expr // the handler passed to Handle, nil for HandleErr
+	hoisted string   // variable holding onError when it cannot be applied in place
+	reset   bool     // whether unthrown errors must be cleared from the error variable
+}
+
+type expander struct {
+	pkg     *codemod.Package
+	file    *codemod.File
+	name    string // local name of the errless package
+	imports map[string]bool
+}
+
+// expand returns the edits that rewrite errless calls in f into explicit error checks.
+func expand(pkg *codemod.Package, f *codemod.File) []codemod.Edit {
+	x := &expander{pkg: pkg, file: f, name: codemod.ImportName(f.AST)}
+	if x.name == "" || x.name == "_" || x.name == "." {
+		return nil
+	}
+
+	var edits []codemod.Edit
+	ast.Inspect(f.AST, func(n ast.Node) bool {
+		var group []codemod.Edit
+		var imports map[string]bool
+		switch fn := n.(type) {
+		case *ast.FuncDecl:
+			if fn.Body != nil {
+				group, imports = x.function(fn.Type, fn.Body)
+			}
+		case *ast.FuncLit:
+			group, imports = x.function(fn.Type, fn.Body)
+		}
+		var accepted bool
+		if edits, accepted = codemod.Accept(edits, group); accepted {
+			for path := range imports {
+				x.require(path)
+			}
+		}
+		return true
+	})
+	if len(edits) == 0 {
+		return nil
+	}
+	for path := range x.imports {
+		if codemod.ImportSpec(f.AST, path) == nil {
+			edits = append(edits, pkg.AddImport(f, path))
+		}
+	}
+	if !x.imports[codemod.ImportPath] && !x.referenced(f.AST, edits) {
+		edits = append(edits, pkg.RemoveImport(f, codemod.ImportSpec(f.AST, codemod.ImportPath)))
+	}
+	return edits
+}
+
+func (x *expander) require(path string) {
+	if x.imports == nil {
+		x.imports = map[string]bool{}
+	}
+	x.imports[path] = true
+}
+
+// function returns the edits for a single function and the imports they need.
+func (x *expander) function(typ *ast.FuncType, body *ast.BlockStmt) ([]codemod.Edit, map[string]bool) {
+	fn := x.prepare(typ, body)
+	if fn == nil {
+		return nil, nil
+	}
+	imports := map[string]bool{}
+	var edits []codemod.Edit
+	var expanded []ast.Node
+	visitStatements(body.List, func(stmt ast.Stmt, topLevel bool) {
+		s := x.parse(stmt)
+		if s == nil {
+			return
+		}
+		text, ok := x.expandSite(fn, s, topLevel, imports)
+		if !ok {
+			return
+		}
+		edits = append(edits, codemod.Edit{Start: x.pkg.Offset(stmt.Pos()), End: x.pkg.Offset(stmt.End()), Text: text})
+		expanded = append(expanded, stmt)
+	})
+	if len(edits) == 0 {
+		return nil, nil
+	}
+
+	keep := x.usesErrless(body, append(expanded, fn.handle))
+	switch {
+	case fn.hoisted != "":
+		text := fn.hoisted + " := " + x.text(fn.onError)
+		if keep {
+			text += "\ndefer " + x.name + ".Handle(&" + fn.errName + ", " + fn.hoisted + ")"
+		}
+		edits = append(edits, codemod.Edit{Start: x.pkg.Offset(fn.handle.Pos()), End: x.pkg.Offset(fn.handle.End()), Text: text})
+	case !keep:
+		edits = append(edits, x.pkg.DeleteLines(x.file, fn.handle))
+	}
+	return edits, imports
+}
+
+// prepare returns the function if it defers errless.Handle or errless.HandleErr on its error result.
+func (x *expander) prepare(typ *ast.FuncType, body *ast.BlockStmt) *function {
+	fn := &function{typ: typ, body: body, scope: x.pkg.Info.Scopes[typ]}
+	if fn.scope == nil || typ.Results == nil {
+		return nil
+	}
+	for _, field := range typ.Results.List {
+		for _, name := range field.Names {
+			fn.results = append(fn.results, x.pkg.Info.Defs[name])
+		}
+	}
+	if len(fn.results) == 0 {
+		return nil
+	}
+
+	for _, stmt := range body.List {
+		d, ok := stmt.(*ast.DeferStmt)
+		if !ok || !x.isErrless(d.Call.Fun, "Handle", "HandleErr") {
+			continue
+		}
+		unary, ok := d.Call.Args[0].(*ast.UnaryExpr)
+		if !ok || unary.Op != token.AND {
+			return nil
+		}
+		id, ok := unary.X.(*ast.Ident)
+		if !ok || x.pkg.Info.ObjectOf(id) != fn.results[len(fn.results)-1] {
+			return nil
+		}
+		fn.handle, fn.errName = d, id.Name
+		if len(d.Call.Args) == 2 && !x.isErrless(d.Call.Args[1], "EmptyHandler") {
+			fn.onError = d.Call.Args[1]
+		}
+	}
+	if fn.handle == nil {
+		return nil
+	}
+	if fn.onError != nil && !x.inline(fn.onError) {
+		fn.hoisted = x.unusedName(fn.scope.Innermost(body.Lbrace), "handleErr", body.Lbrace)
+	}
+	fn.reset = x.readsError(fn)
+	return fn
+}
+
+// parse returns the errless call chain used by stmt, or nil.
+func (x *expander) parse(stmt ast.Stmt) *site {
+	s := &site{stmt: stmt}
+	var expr ast.Expr
+	switch st := stmt.(type) {
+	case *ast.ExprStmt:
+		expr = st.X
+	case *ast.AssignStmt:
+		if len(st.Rhs) != 1 || (st.Tok != token.DEFINE && st.Tok != token.ASSIGN) {
+			return nil
+		}
+		expr, s.lhs, s.tok = st.Rhs[0], st.Lhs, st.Tok
+	default:
+		return nil
+	}
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	if x.isPackage(sel.X) {
+		if !x.parseThrow(s, sel.Sel.Name, call.Args) {
+			return nil
+		}
+	} else if !x.parseChain(s, sel, call.Args) {
+		return nil
+	}
+	if s.lhs != nil && len(s.lhs) != s.values {
+		return nil
+	}
+	if s.kind == terminalOr && s.lhs != nil {
+		return nil
+	}
+	return s
+}
+
+// parseThrow parses errless.Throw(err, handlers...) and errless.ThrowN(f()).
+func (x *expander) parseThrow(s *site, name string, args []ast.Expr) bool {
+	if name == "Throw" {
+		if len(args) == 0 {
+			return false
+		}
+		s.call = args[0]
+		for _, h := range args[1:] {
+			s.handlers = append(s.handlers, x.handler(h))
+		}
+		return true
+	}
+	n, ok := arity(name, "Throw")
+	if !ok || n == 0 || len(args) != 1 {
+		return false
+	}
+	s.call, s.values = args[0], n
+	return true
+}
+
+// parseChain parses TryN(f()).If(...)...Err(...) and the other ParamsN methods. Chains
+// checked in a Scope with In are not parsed, as the scope records the error instead of
+// returning it.
+func (x *expander) parseChain(s *site, sel *ast.SelectorExpr, args []ast.Expr) bool {
+	switch sel.Sel.Name {
+	case "Err", "E":
+		for _, h := range args {
+			s.handlers = append(s.handlers, x.handler(h))
+		}
+	case "ErrMessage", "ErrWrap":
+		s.handlers = []handler{{message: args[0]}}
+	case "ErrPublic":
+		s.handlers = []handler{{public: args[0]}}
+	case "Fallback":
+		s.kind, s.fallback = terminalFallback, args[0]
+	case "Or":
+		s.kind, s.fallback = terminalOr, args[0]
+	default:
+		return false
+	}
+
+	receiver := sel.X
+	for {
+		call, ok := receiver.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+		if x.isPackage(sel.X) {
+			n, ok := arity(sel.Sel.Name, "Try")
+			if !ok || len(call.Args) != 1 {
+				return false
+			}
+			s.call, s.values = call.Args[0], n
+			break
+		}
+		switch sel.Sel.Name {
+		case "If":
+			group := make([]filter, 0, len(call.Args))
+			for _, arg := range call.Args {
+				group = append(group, x.filter(arg))
+			}
+			s.groups = append([][]filter{group}, s.groups...)
+		case "IfIs":
+			s.groups = append([][]filter{{{kind: "Is", expr: call.Args[0]}}}, s.groups...)
+		case "IfNot":
+			s.groups = append([][]filter{{{kind: "IsNot", expr: call.Args[0]}}}, s.groups...)
+		case "Label":
+		default:
+			return false
+		}
+		receiver = sel.X
+	}
+	if s.kind == terminalFallback && s.values == 0 {
+		return false
+	}
+	return s.kind != terminalOr || s.values == 0
+}
+
+func (x *expander) handler(expr ast.Expr) handler {
+	if call, ok := expr.(*ast.CallExpr); ok && len(call.Args) == 1 && x.isErrless(call.Fun, "Message", "Wrap") {
+		return handler{message: call.Args[0]}
+	}
+	if call, ok := expr.(*ast.CallExpr); ok && len(call.Args) == 1 && x.isErrless(call.Fun, "Public") {
+		return handler{public: call.Args[0]}
+	}
+	return handler{expr: expr}
+}
+
+func (x *expander) filter(expr ast.Expr) filter {
+	if call, ok := expr.(*ast.CallExpr); ok && len(call.Args) == 1 {
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && x.isErrless(sel, "Is", "IsNot", "Contains") {
+			return filter{kind: sel.Sel.Name, expr: call.Args[0]}
+		}
+	}
+	return filter{expr: expr}
+}
+
+// expandSite returns the statements replacing s.
+func (x *expander) expandSite(fn *function, s *site, topLevel bool, imports map[string]bool) (string, bool) {
+	pos := s.stmt.Pos()
+	if !x.resultsVisible(fn, pos) || (!topLevel && x.shadowsResult(fn, s)) {
+		return "", false
+	}
+	e := fn.errName
+	call := x.text(s.call)
+	if s.kind == terminalOr {
+		return x.text(s.fallback) + "(" + call + ")", true
+	}
+
+	var b strings.Builder
+	filters := make([]string, 0, len(s.groups))
+	for _, group := range s.groups {
+		text, ok := x.group(group, e, pos, imports)
+		if !ok {
+			return "", false
+		}
+		filters = append(filters, text)
+	}
+	cond := strings.Join(append([]string{e + " != nil"}, filters...), " && ")
+
+	if s.kind == terminalFallback {
+		b.WriteString(strings.Repeat("_, ", s.values) + e + " = " + call + "\n")
+		if len(filters) > 0 {
+			b.WriteString("if " + e + " != nil && " + negate(filters) + " {\n")
+			b.WriteString(x.final(fn, e, imports) + "\n}\n")
+		}
+		apply := x.text(s.fallback) + "(" + e + ")"
+		if blank(s.lhs) {
+			b.WriteString(apply)
+		} else {
+			b.WriteString(x.exprs(s.lhs) + " " + s.tok.String() + " " + apply)
+		}
+		if fn.reset {
+			b.WriteString("\n" + e + " = nil")
+		}
+		return b.String(), true
+	}
+
+	chain, ok := x.chain(fn, s.handlers, e, pos, imports)
+	if !ok {
+		return "", false
+	}
+	// the chain only falls through with a nil error, so a non-nil one was let through by the filters
+	end := "\n}"
+	if fn.reset && len(filters) > 0 {
+		end += "\n" + e + " = nil"
+	}
+	if blank(s.lhs) {
+		b.WriteString("if " + strings.Repeat("_, ", s.values) + e + " = " + call + "; " + cond + " {\n")
+		b.WriteString(chain + end)
+		return b.String(), true
+	}
+
+	tok := token.ASSIGN
+	if s.tok == token.DEFINE {
+		if topLevel {
+			tok = token.DEFINE
+		} else {
+			decls, ok := x.declarations(s)
+			if !ok {
+				return "", false
+			}
+			b.WriteString(decls)
+		}
+	}
+	b.WriteString(x.exprs(s.lhs) + ", " + e + " " + tok.String() + " " + call + "\n")
+	b.WriteString("if " + cond + " {\n" + chain + end)
+	return b.String(), true
+}
+
+// chain returns the statements applying handlers to the error before the function returns.
+func (x *expander) chain(fn *function, handlers []handler, e string, pos token.Pos, imports map[string]bool) (string, bool) {
+	if len(handlers) == 0 {
+		return x.final(fn, e, imports), true
+	}
+	apply, nonNil, ok := x.apply(handlers[0], e, pos, imports)
+	if !ok {
+		return "", false
+	}
+	rest, ok := x.chain(fn, handlers[1:], e, pos, imports)
+	switch {
+	case !ok:
+		return "", false
+	case apply == "":
+		return rest, true
+	case nonNil:
+		return e + " = " + apply + "\n" + rest, true
+	default:
+		return "if " + e + " = " + apply + "; " + e + " != nil {\n" + rest + "\n}", true
+	}
+}
+
+// final returns the statements of the deferred handler.
+func (x *expander) final(fn *function, e string, imports map[string]bool) string {
+	switch {
+	case fn.hoisted != "":
+		return e + " = " + fn.hoisted + "(" + e + ")\nreturn"
+	case fn.onError != nil:
+		if apply, _, ok := x.apply(x.handler(fn.onError), e, fn.body.Lbrace, imports); ok && apply != "" {
+			return e + " = " + apply + "\nreturn"
+		}
+	}
+	return "return"
+}
+
+// apply returns the expression applying h to the error, "" if h returns the error unchanged,
+// and whether the result is known to be non-nil.
+func (x *expander) apply(h handler, e string, pos token.Pos, imports map[string]bool) (string, bool, bool) {
+	if h.message != nil {
+		fmtName, ok := x.stdName("fmt", pos, imports)
+		if !ok {
+			return "", false, false
+		}
+		format := x.text(h.message) + ` + " - error: %s"`
+		if lit, ok := h.message.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			value, _ := strconv.Unquote(lit.Value)
+			format = strconv.Quote(value + " - error: %s")
+		}
+		return fmtName + ".Errorf(" + format + ", " + e + ")", true, true
+	}
+	if h.public != nil {
+		imports[codemod.ImportPath] = true
+		return "&" + x.name + ".PublicError{Err: " + e + ", Message: " + x.text(h.public) + "}", true, true
+	}
+	if x.isErrless(h.expr, "EmptyHandler") {
+		return "", false, true
+	}
+	return x.text(h.expr) + "(" + e + ")", false, true
+}
+
+// group returns the condition of an If call: any of its filters matches the error.
+func (x *expander) group(group []filter, e string, pos token.Pos, imports map[string]bool) (string, bool) {
+	conds := make([]string, 0, len(group))
+	for _, f := range group {
+		var cond string
+		switch f.kind {
+		case "Is", "IsNot":
+			errorsName, ok := x.stdName("errors", pos, imports)
+			if !ok {
+				return "", false
+			}
+			cond = errorsName + ".Is(" + e + ", " + x.text(f.expr) + ")"
+			if f.kind == "IsNot" {
+				cond = "!" + cond
+			}
+		case "Contains":
+			stringsName, ok := x.stdName("strings", pos, imports)
+			if !ok {
+				return "", false
+			}
+			cond = stringsName + ".Contains(" + e + ".Error(), " + x.text(f.expr) + ")"
+		default:
+			cond = x.text(f.expr) + "(" + e + ")"
+		}
+		conds = append(conds, cond)
+	}
+	if len(conds) == 1 {
+		return conds[0], true
+	}
+	return "(" + strings.Join(conds, " || ") + ")", true
+}
+
+// declarations returns the var declarations for the values a nested := site defines,
+// so the error result is assigned instead of shadowed.
+func (x *expander) declarations(s *site) (string, bool) {
+	var valueTypes []types.Type
+	switch t := x.pkg.Info.TypeOf(s.call).(type) {
+	case *types.Tuple:
+		for i := 0; i < t.Len()-1; i++ {
+			valueTypes = append(valueTypes, t.At(i).Type())
+		}
+	default:
+		return "", false
+	}
+	if len(valueTypes) != len(s.lhs) {
+		return "", false
+	}
+	var b strings.Builder
+	for i, expr := range s.lhs {
+		id, ok := expr.(*ast.Ident)
+		if !ok || x.pkg.Info.Defs[id] == nil {
+			continue
+		}
+		typ, ok := x.typeString(valueTypes[i])
+		if !ok {
+			return "", false
+		}
+		b.WriteString("var " + id.Name + " " + typ + "\n")
+	}
+	return b.String(), true
+}
+
+func (x *expander) typeString(t types.Type) (string, bool) {
+	ok := true
+	s := types.TypeString(t, func(p *types.Package) string {
+		for _, spec := range x.file.AST.Imports {
+			if path, _ := strconv.Unquote(spec.Path.Value); path == p.Path() {
+				if spec.Name != nil {
+					return spec.Name.Name
+				}
+				return p.Name()
+			}
+		}
+		if p.Name() != x.file.AST.Name.Name {
+			ok = false
+		}
+		return ""
+	})
+	return s, ok
+}
+
+// stdName returns the name to refer to a standard library package at pos, recording the import.
+func (x *expander) stdName(path string, pos token.Pos, imports map[string]bool) (string, bool) {
+	name := path
+	if spec := codemod.ImportSpec(x.file.AST, path); spec != nil && spec.Name != nil {
+		name = spec.Name.Name
+		if name == "_" || name == "." {
+			return "", false
+		}
+	}
+	if scope := x.pkg.Info.Scopes[x.file.AST]; scope != nil {
+		if _, obj := scope.Innermost(pos).LookupParent(name, pos); obj != nil {
+			if pkgName, ok := obj.(*types.PkgName); !ok || pkgName.Imported().Path() != path {
+				return "", false
+			}
+		}
+	}
+	imports[path] = true
+	return name, true
+}
+
+// resultsVisible reports whether the named results are not shadowed at pos, so a bare return works.
+func (x *expander) resultsVisible(fn *function, pos token.Pos) bool {
+	scope := fn.scope.Innermost(pos)
+	for _, obj := range fn.results {
+		if obj == nil || obj.Name() == "_" {
+			continue
+		}
+		if _, found := scope.LookupParent(obj.Name(), pos); found != obj {
+			return false
+		}
+	}
+	return true
+}
+
+// shadowsResult reports whether s declares a variable with the name of a result,
+// which would hide the result from the bare return of the expansion.
+func (x *expander) shadowsResult(fn *function, s *site) bool {
+	if s.tok != token.DEFINE {
+		return false
+	}
+	for _, expr := range s.lhs {
+		id, ok := expr.(*ast.Ident)
+		if !ok || x.pkg.Info.Defs[id] == nil {
+			continue
+		}
+		for _, obj := range fn.results {
+			if obj != nil && obj.Name() == id.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readsError reports whether the function reads its error result or returns it with a bare return.
+func (x *expander) readsError(fn *function) bool {
+	errObj := fn.results[len(fn.results)-1]
+	reads := false
+	ast.Inspect(fn.body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.DeferStmt:
+			return node != fn.handle
+		case *ast.ReturnStmt:
+			reads = reads || len(node.Results) == 0
+		case *ast.Ident:
+			reads = reads || x.pkg.Info.Uses[node] == errObj
+		}
+		return !reads
+	})
+	return reads
+}
+
+// usesErrless reports whether body refers to errless outside the skipped nodes.
+func (x *expander) usesErrless(body ast.Node, skipped []ast.Node) bool {
+	used := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		for _, s := range skipped {
+			if n == s {
+				return false
+			}
+		}
+		if sel, ok := n.(*ast.SelectorExpr); ok && x.isPackage(sel.X) {
+			used = true
+		}
+		return !used
+	})
+	return used
+}
+
+// referenced reports whether f still refers to errless outside the edited ranges.
+func (x *expander) referenced(f *ast.File, edits []codemod.Edit) bool {
+	used := false
+	ast.Inspect(f, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok || !x.isPackage(sel.X) {
+			return !used
+		}
+		start, end := x.pkg.Offset(sel.Pos()), x.pkg.Offset(sel.End())
+		for _, e := range edits {
+			if start >= e.Start && end <= e.End && e.Start != e.End {
+				return false
+			}
+		}
+		used = true
+		return false
+	})
+	return used
+}
+
+func (x *expander) unusedName(scope *types.Scope, name string, pos token.Pos) string {
+	candidate := name
+	for i := 2; ; i++ {
+		if _, obj := scope.LookupParent(candidate, pos); obj == nil {
+			return candidate
+		}
+		candidate = name + strconv.Itoa(i)
+	}
+}
+
+// inline reports whether the handler passed to Handle can be evaluated at each call site.
+func (x *expander) inline(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		return true
+	case *ast.CallExpr:
+		return len(e.Args) == 1 && x.isErrless(e.Fun, "Message", "Wrap")
+	}
+	return false
+}
+
+func (x *expander) isPackage(expr ast.Expr) bool {
+	id, ok := expr.(*ast.Ident)
+	if !ok || id.Name != x.name {
+		return false
+	}
+	obj := x.pkg.Info.Uses[id]
+	_, isPkg := obj.(*types.PkgName)
+	return obj == nil || isPkg
+}
+
+func (x *expander) isErrless(expr ast.Expr, names ...string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || !x.isPackage(sel.X) {
+		return false
+	}
+	for _, name := range names {
+		if sel.Sel.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (x *expander) text(node ast.Node) string {
+	return x.pkg.Text(x.file, node)
+}
+
+func (x *expander) exprs(list []ast.Expr) string {
+	texts := make([]string, 0, len(list))
+	for _, expr := range list {
+		texts = append(texts, x.text(expr))
+	}
+	return strings.Join(texts, ", ")
+}
+
+// arity returns N for the name prefixN, with "" meaning 0.
+func arity(name, prefix string) (int, bool) {
+	if !strings.HasPrefix(name, prefix) {
+		return 0, false
+	}
+	suffix := strings.TrimPrefix(name, prefix)
+	if suffix == "" {
+		return 0, true
+	}
+	n, err := strconv.Atoi(suffix)
+	return n, err == nil && n >= 1 && n <= 5
+}
+
+// negate returns the condition that not all filters match.
+func negate(filters []string) string {
+	if len(filters) > 1 {
+		return "!(" + strings.Join(filters, " && ") + ")"
+	}
+	if strings.HasPrefix(filters[0], "!") {
+		return strings.TrimPrefix(filters[0], "!")
+	}
+	return "!" + filters[0]
+}
+
+func blank(lhs []ast.Expr) bool {
+	for _, expr := range lhs {
+		if id, ok := expr.(*ast.Ident); !ok || id.Name != "_" {
+			return false
+		}
+	}
+	return true
+}
+
+// visitStatements calls visit for the statements of list and the blocks nested in them,
+// without descending into function literals.
+func visitStatements(list []ast.Stmt, visit func(stmt ast.Stmt, topLevel bool)) {
+	var walk func(list []ast.Stmt, topLevel bool)
+	walk = func(list []ast.Stmt, topLevel bool) {
+		for _, stmt := range list {
+			visit(stmt, topLevel)
+			for _, nested := range codemod.NestedBlocks(stmt) {
+				walk(nested, false)
+			}
+		}
+	}
+	walk(list, true)
+}
@@ -0,0 +1,42 @@
+//go:build test
+
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mfatihercik/errless/internal/codemod"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestExpand(t *testing.T) {
+	inputs, err := filepath.Glob("testdata/*.input")
+	require.NoError(t, err)
+	require.NotEmpty(t, inputs)
+
+	loader := codemod.NewLoader()
+	for _, input := range inputs {
+		input := input
+		t.Run(filepath.Base(input), func(t *testing.T) {
+			src, err := os.ReadFile(input)
+			require.NoError(t, err)
+			out, err := loader.Rewrite([]string{input}, [][]byte{src}, expand)
+			require.NoError(t, err)
+
+			golden := strings.TrimSuffix(input, ".input") + ".golden"
+			if *update {
+				require.NoError(t, os.WriteFile(golden, out[0], 0o600))
+			}
+			want, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(out[0]))
+		})
+	}
+}
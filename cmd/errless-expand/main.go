@@ -0,0 +1,47 @@
+// Command errless-expand rewrites errless calls back into explicit error checks, so a
+// package can drop its dependency on errless.
+//
+// In a function with a deferred errless.Handle or errless.HandleErr
+//
+//	func load(id string) (u User, err error) {
+//		defer errless.Handle(&err, wrap)
+//		u = errless.Try1(find(id)).ErrMessage("find")
+//		return u, nil
+//	}
+//
+// each call is expanded into a check that runs the same handlers
+//
+//	func load(id string) (u User, err error) {
+//		u, err = find(id)
+//		if err != nil {
+//			err = fmt.Errorf("find - error: %s", err)
+//			err = wrap(err)
+//			return
+//		}
+//		return u, nil
+//	}
+//
+// and the deferred handler is removed once no errless call is left in the function.
+// ErrPublic and errless.Public are expanded into an errless.PublicError, which keeps
+// the import. Functions that rely on a deferred Catch, chains checked in a Scope with
+// In, and errless calls nested inside other expressions, are left unchanged. A function that relies on its deferred handler
+// to recover throws from the functions it calls keeps that handler only while it
+// still contains errless calls, so such callers should be expanded together with
+// their callees.
+//
+// Usage:
+//
+//	errless-expand [-w | -diff] [path ...]
+//
+// Without flags the rewritten files are printed to standard output.
+package main
+
+import (
+	"os"
+
+	"github.com/mfatihercik/errless/internal/codemod"
+)
+
+func main() {
+	os.Exit(codemod.Main("errless-expand", expand))
+}
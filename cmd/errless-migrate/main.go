@@ -0,0 +1,33 @@
+// Command errless-migrate rewrites canonical error checks into errless calls.
+//
+// A call followed by an early return of its error
+//
+//	x, err := f()
+//	if err != nil {
+//		return 0, err
+//	}
+//
+// is rewritten to
+//
+//	x := errless.Try1(f()).Err()
+//
+// and the enclosing function gets a named err result with a deferred errless.HandleErr,
+// or errless.HandleZero when it sets a named result before the check.
+// Functions that defer Catch, or Handle with a handler other than EmptyHandler, are skipped.
+//
+// Usage:
+//
+//	errless-migrate [-w | -diff] [path ...]
+//
+// Without flags the rewritten files are printed to standard output.
+package main
+
+import (
+	"os"
+
+	"github.com/mfatihercik/errless/internal/codemod"
+)
+
+func main() {
+	os.Exit(codemod.Main("errless-migrate", migrate))
+}
@@ -0,0 +1,458 @@
+package main
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"github.com/mfatihercik/errless/internal/codemod"
+)
+
+// maxValues is the number of values the largest TryN function accepts.
+const maxValues = 5
+
+var errorType = types.Universe.Lookup("error").Type()
+
+// errless handlers that already recover throws for the function they are deferred in.
+var deferredHandlers = map[string]bool{"HandleErr": true, "Handle": true, "Catch": true}
+
+type migrator struct {
+	pkg  *codemod.Package
+	file *codemod.File
+	name string // local name of the errless package
+}
+
+// candidate is a call followed by an early return of its error.
+type candidate struct {
+	assign *ast.AssignStmt
+	check  *ast.IfStmt
+	errObj types.Object
+}
+
+// migrate returns the edits that rewrite canonical error checks in f into errless calls.
+func migrate(pkg *codemod.Package, f *codemod.File) []codemod.Edit {
+	m := &migrator{pkg: pkg, file: f, name: codemod.ImportName(f.AST)}
+	if m.name == "_" || m.name == "." {
+		return nil
+	}
+	addImport := m.name == ""
+	if addImport {
+		m.name = "errless"
+	}
+
+	var edits []codemod.Edit
+	ast.Inspect(f.AST, func(n ast.Node) bool {
+		switch fn := n.(type) {
+		case *ast.FuncDecl:
+			if fn.Body != nil {
+				edits, _ = codemod.Accept(edits, m.function(fn.Type, fn.Body))
+			}
+		case *ast.FuncLit:
+			edits, _ = codemod.Accept(edits, m.function(fn.Type, fn.Body))
+		}
+		return true
+	})
+	if len(edits) > 0 && addImport {
+		edits = append(edits, pkg.AddImport(f, codemod.ImportPath))
+	}
+	return edits
+}
+
+// function returns the edits for a single function, or nil if nothing in it can be migrated.
+func (m *migrator) function(typ *ast.FuncType, body *ast.BlockStmt) []codemod.Edit {
+	results := typ.Results
+	if results == nil || len(results.List) == 0 {
+		return nil
+	}
+	last := results.List[len(results.List)-1]
+	if t := m.pkg.Info.TypeOf(last.Type); t == nil || !types.Identical(t, errorType) {
+		return nil
+	}
+	named := len(last.Names) > 0
+	errName := "err"
+	if named {
+		errName = last.Names[len(last.Names)-1].Name
+		if errName == "_" {
+			return nil
+		}
+	}
+
+	candidates := m.candidates(body, resultCount(results))
+	handler := m.deferredHandler(body)
+	if handler != nil && !m.passesThrough(handler, errName) {
+		return nil
+	}
+	candidates = m.exclusive(body, candidates, handler)
+	if len(candidates) == 0 {
+		return nil
+	}
+	if !named && m.declaresOutside(typ, errName, candidates) {
+		return nil
+	}
+	// HandleErr leaves the other named results as they are, while the early returns zeroed them.
+	var touched *ast.Ident
+	if named {
+		values := m.touchedResults(results, body)
+		if len(values) > 1 || len(values) == 1 && handler != nil {
+			return nil
+		}
+		if len(values) == 1 {
+			touched = values[0]
+		}
+	}
+
+	edits := make([]codemod.Edit, 0, len(candidates)+2)
+	for _, c := range candidates {
+		edits = append(edits, codemod.Edit{
+			Start: m.pkg.Offset(c.assign.Pos()),
+			End:   m.pkg.Offset(c.check.End()),
+			Text:  m.replacement(c),
+		})
+	}
+	if !named {
+		edits = append(edits, codemod.Edit{
+			Start: m.pkg.Offset(results.Pos()),
+			End:   m.pkg.Offset(results.End()),
+			Text:  m.namedResults(results, errName),
+		})
+	}
+	if handler == nil {
+		deferred := m.name + ".HandleErr(&" + errName + ")"
+		if touched != nil {
+			deferred = m.name + ".HandleZero(&" + touched.Name + ", &" + errName + ")"
+		}
+		at := m.pkg.Offset(body.Lbrace) + 1
+		edits = append(edits, codemod.Edit{Start: at, End: at, Text: "\ndefer " + deferred})
+	}
+	return edits
+}
+
+// candidates returns the error checks in body, without descending into function literals.
+func (m *migrator) candidates(body *ast.BlockStmt, results int) []candidate {
+	var found []candidate
+	var visit func(list []ast.Stmt)
+	visit = func(list []ast.Stmt) {
+		for i, stmt := range list {
+			if i+1 < len(list) {
+				if c, ok := m.match(stmt, list[i+1], results); ok {
+					found = append(found, c)
+				}
+			}
+			for _, nested := range codemod.NestedBlocks(stmt) {
+				visit(nested)
+			}
+		}
+	}
+	visit(body.List)
+	return found
+}
+
+// match reports whether stmt and next are a call followed by an early return of its error.
+func (m *migrator) match(stmt, next ast.Stmt, results int) (candidate, bool) {
+	assign, ok := stmt.(*ast.AssignStmt)
+	if !ok || (assign.Tok != token.DEFINE && assign.Tok != token.ASSIGN) || len(assign.Rhs) != 1 {
+		return candidate{}, false
+	}
+	call, ok := assign.Rhs[0].(*ast.CallExpr)
+	if !ok || len(assign.Lhs) > maxValues+1 || !m.returnsError(call, len(assign.Lhs)) {
+		return candidate{}, false
+	}
+	errIdent, ok := assign.Lhs[len(assign.Lhs)-1].(*ast.Ident)
+	if !ok || errIdent.Name == "_" {
+		return candidate{}, false
+	}
+	errObj := m.pkg.Info.ObjectOf(errIdent)
+	check, ok := next.(*ast.IfStmt)
+	if errObj == nil || !ok || check.Init != nil || check.Else != nil || !m.isNotNil(check.Cond, errObj) {
+		return candidate{}, false
+	}
+	if len(check.Body.List) != 1 {
+		return candidate{}, false
+	}
+	ret, ok := check.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != results || !m.returnsErr(ret, errObj) || m.hasComments(assign.End(), check.End()) {
+		return candidate{}, false
+	}
+	return candidate{assign: assign, check: check, errObj: errObj}, true
+}
+
+// returnsError reports whether call returns values values, the last of them an error.
+func (m *migrator) returnsError(call *ast.CallExpr, values int) bool {
+	switch t := m.pkg.Info.TypeOf(call).(type) {
+	case nil:
+		return false
+	case *types.Tuple:
+		return t.Len() == values && types.Identical(t.At(t.Len()-1).Type(), errorType)
+	default:
+		return values == 1 && types.Identical(t, errorType)
+	}
+}
+
+func (m *migrator) isNotNil(cond ast.Expr, errObj types.Object) bool {
+	bin, ok := cond.(*ast.BinaryExpr)
+	if !ok || bin.Op != token.NEQ {
+		return false
+	}
+	x, ok := bin.X.(*ast.Ident)
+	return ok && m.pkg.Info.ObjectOf(x) == errObj && m.pkg.Info.Types[bin.Y].IsNil()
+}
+
+// returnsErr reports whether ret returns zero values followed by the checked error.
+func (m *migrator) returnsErr(ret *ast.ReturnStmt, errObj types.Object) bool {
+	last, ok := ret.Results[len(ret.Results)-1].(*ast.Ident)
+	if !ok || m.pkg.Info.ObjectOf(last) != errObj {
+		return false
+	}
+	for _, expr := range ret.Results[:len(ret.Results)-1] {
+		if !m.isZero(expr) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *migrator) isZero(expr ast.Expr) bool {
+	tv, ok := m.pkg.Info.Types[expr]
+	if !ok {
+		return false
+	}
+	if tv.IsNil() {
+		return true
+	}
+	if tv.Value != nil {
+		switch tv.Value.Kind() {
+		case constant.Bool:
+			return !constant.BoolVal(tv.Value)
+		case constant.String:
+			return constant.StringVal(tv.Value) == ""
+		case constant.Int, constant.Float, constant.Complex:
+			return constant.Sign(tv.Value) == 0
+		}
+		return false
+	}
+	if lit, ok := expr.(*ast.CompositeLit); ok && len(lit.Elts) == 0 {
+		switch tv.Type.Underlying().(type) {
+		case *types.Struct, *types.Array:
+			return true
+		}
+	}
+	return false
+}
+
+func (m *migrator) hasComments(from, to token.Pos) bool {
+	for _, group := range m.file.AST.Comments {
+		if group.Pos() > from && group.End() < to {
+			return true
+		}
+	}
+	return false
+}
+
+// deferredHandler returns the errless handler deferred at the top of body, if any.
+func (m *migrator) deferredHandler(body *ast.BlockStmt) *ast.DeferStmt {
+	for _, stmt := range body.List {
+		d, ok := stmt.(*ast.DeferStmt)
+		if !ok {
+			continue
+		}
+		if sel, ok := d.Call.Fun.(*ast.SelectorExpr); ok && deferredHandlers[sel.Sel.Name] {
+			if x, ok := sel.X.(*ast.Ident); ok && x.Name == m.name {
+				return d
+			}
+		}
+	}
+	return nil
+}
+
+// passesThrough reports whether the deferred handler stores thrown errors in errName unchanged,
+// like the early returns it replaces. Catch swallows them and Handle may transform them.
+func (m *migrator) passesThrough(d *ast.DeferStmt, errName string) bool {
+	args := d.Call.Args
+	if len(args) == 0 {
+		return false
+	}
+	ref, ok := args[0].(*ast.UnaryExpr)
+	if !ok || ref.Op != token.AND {
+		return false
+	}
+	if id, ok := ref.X.(*ast.Ident); !ok || id.Name != errName {
+		return false
+	}
+	switch d.Call.Fun.(*ast.SelectorExpr).Sel.Name {
+	case "HandleErr":
+		return len(args) == 1
+	case "Handle":
+		return len(args) == 2 && m.isErrless(args[1], "EmptyHandler")
+	}
+	return false
+}
+
+// isErrless reports whether expr refers to the errless identifier name.
+func (m *migrator) isErrless(expr ast.Expr, name string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	return ok && x.Name == m.name
+}
+
+// touchedResults returns the named non-error results that body refers to outside of return statements.
+// Such a result may hold a value when a check fails, where the early return would have zeroed it.
+func (m *migrator) touchedResults(results *ast.FieldList, body *ast.BlockStmt) []*ast.Ident {
+	objects := map[types.Object]*ast.Ident{}
+	for _, field := range results.List[:len(results.List)-1] {
+		for _, name := range field.Names {
+			if name.Name != "_" {
+				objects[m.pkg.Info.Defs[name]] = name
+			}
+		}
+	}
+	last := results.List[len(results.List)-1]
+	for _, name := range last.Names[:len(last.Names)-1] {
+		if name.Name != "_" {
+			objects[m.pkg.Info.Defs[name]] = name
+		}
+	}
+	var touched []*ast.Ident
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ReturnStmt:
+			for _, res := range n.Results {
+				if _, ok := ast.Unparen(res).(*ast.Ident); !ok {
+					ast.Inspect(res, visit)
+				}
+			}
+			return false
+		case *ast.Ident:
+			obj := m.pkg.Info.Uses[n]
+			if name := objects[obj]; name != nil {
+				touched = append(touched, name)
+				delete(objects, obj)
+			}
+		}
+		return true
+	}
+	ast.Inspect(body, visit)
+	return touched
+}
+
+// exclusive drops candidates whose error variable is also used by code that is not rewritten.
+func (m *migrator) exclusive(body *ast.BlockStmt, candidates []candidate, handler *ast.DeferStmt) []candidate {
+	consumed := make([]ast.Node, 0, len(candidates)+1)
+	for _, c := range candidates {
+		consumed = append(consumed, span{c.assign.Pos(), c.check.End()})
+	}
+	if handler != nil {
+		consumed = append(consumed, handler)
+	}
+	shared := map[types.Object]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		obj := m.pkg.Info.ObjectOf(id)
+		for _, node := range consumed {
+			if id.Pos() >= node.Pos() && id.End() <= node.End() {
+				return true
+			}
+		}
+		shared[obj] = true
+		return true
+	})
+	kept := candidates[:0]
+	for _, c := range candidates {
+		if !shared[c.errObj] {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// declaresOutside reports whether naming the err result would clash with a parameter
+// or a variable of the function body that is not removed by the rewrite.
+func (m *migrator) declaresOutside(typ *ast.FuncType, errName string, candidates []candidate) bool {
+	scope := m.pkg.Info.Scopes[typ]
+	if scope == nil {
+		return true
+	}
+	obj := scope.Lookup(errName)
+	if obj == nil {
+		return false
+	}
+	for _, c := range candidates {
+		if c.errObj == obj {
+			return false
+		}
+	}
+	return true
+}
+
+// replacement returns the errless call that replaces c.
+func (m *migrator) replacement(c candidate) string {
+	values := c.assign.Lhs[:len(c.assign.Lhs)-1]
+	try := m.name + ".Try"
+	if len(values) > 0 {
+		try += strconv.Itoa(len(values))
+	}
+	call := try + "(" + m.pkg.Text(m.file, c.assign.Rhs[0]) + ").Err()"
+
+	names := make([]string, 0, len(values))
+	declares, blank := false, true
+	for _, v := range values {
+		if id, ok := v.(*ast.Ident); ok && id.Name == "_" {
+			names = append(names, "_")
+			continue
+		}
+		blank = false
+		if id, ok := v.(*ast.Ident); ok && m.pkg.Info.Defs[id] != nil {
+			declares = true
+		}
+		names = append(names, m.pkg.Text(m.file, v))
+	}
+	if blank {
+		return call
+	}
+	tok := token.ASSIGN
+	if c.assign.Tok == token.DEFINE && declares {
+		tok = token.DEFINE
+	}
+	return strings.Join(names, ", ") + " " + tok.String() + " " + call
+}
+
+// namedResults returns the result list with blank names for the values and errName for the error.
+func (m *migrator) namedResults(results *ast.FieldList, errName string) string {
+	fields := make([]string, 0, len(results.List))
+	for i, field := range results.List {
+		name := "_"
+		if i == len(results.List)-1 {
+			name = errName
+		}
+		fields = append(fields, name+" "+m.pkg.Text(m.file, field.Type))
+	}
+	return "(" + strings.Join(fields, ", ") + ")"
+}
+
+func resultCount(results *ast.FieldList) int {
+	n := 0
+	for _, field := range results.List {
+		if len(field.Names) == 0 {
+			n++
+		}
+		n += len(field.Names)
+	}
+	return n
+}
+
+// span is a source range used to mark statements consumed by the rewrite.
+type span struct {
+	from, to token.Pos
+}
+
+func (s span) Pos() token.Pos { return s.from }
+func (s span) End() token.Pos { return s.to }
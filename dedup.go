@@ -0,0 +1,156 @@
+package errless
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mfatihercik/errless/internal/throw"
+)
+
+// DedupRecord is what a Deduper passes to its sink for a group of identical errors.
+type DedupRecord struct {
+	// Err is the first error of the group.
+	Err error
+	// Site is the call site of the group, as "dir/file.go:line".
+	Site string
+	// Count is the number of errors the record stands for: 1 for the first error of a
+	// window, or the number of duplicates for a summary.
+	Count int
+	// Summary tells that the record sums up the duplicates of Err seen since it was passed on.
+	Summary bool
+	// First and Last are the times of the first and the last error the record stands for.
+	First, Last time.Time
+}
+
+// Deduper passes errors to a sink, such as a logger, once per window: the first error of a
+// call site and code (or message, for errors without code) is passed on at once, and its
+// duplicates within the window are only counted. The count is passed on as a summary when
+// the error is seen again after the window, when the groups of ended windows are evicted
+// by a later error, or by Flush, which should be called before the Deduper is dropped.
+// It uses the clock set by SetClock, and is safe for concurrent use.
+type Deduper struct {
+	window time.Duration
+	sink   func(DedupRecord)
+
+	mu     sync.Mutex
+	groups map[dedupKey]*dedupGroup
+	swept  time.Time // when the groups of ended windows were last evicted
+}
+
+type dedupKey struct {
+	site string
+	key  string
+}
+
+type dedupGroup struct {
+	first       DedupRecord
+	duplicates  int
+	firstRepeat time.Time
+	last        time.Time
+}
+
+// NewDeduper returns a Deduper that passes records to sink.
+func NewDeduper(window time.Duration, sink func(DedupRecord)) *Deduper {
+	return &Deduper{window: window, sink: sink, groups: map[dedupKey]*dedupGroup{}}
+}
+
+// Dedup returns a handler that passes the errors to sink through a new Deduper, and
+// the Flush of that Deduper. The error is passed on unchanged.
+func Dedup(window time.Duration, sink func(DedupRecord)) (handler HandlerFunc, flush func()) {
+	d := NewDeduper(window, sink)
+	return d.Handler, d.Flush
+}
+
+// Handler is a HandlerFunc that records the error, grouped by the call site of the Throw.
+// The error is passed on unchanged.
+func (d *Deduper) Handler(err error) error {
+	if err != nil {
+		d.record(err, throw.Caller())
+	}
+	return err
+}
+
+// HandleErr is used like errless.HandleErr, and also records the caught error, grouped by
+// the call site of its Throw.
+func (d *Deduper) HandleErr(namedErr *error) {
+	exp := recoverException(recover())
+	if exp != nil {
+		recoverTrail(exp, "errless.Deduper.HandleErr")
+		observeRecover(exp, exp.Err)
+		d.record(exp.Err, exp.Site)
+		if namedErr != nil {
+			*namedErr = exp.Err
+		}
+	}
+}
+
+// Flush passes the summaries of the duplicates counted so far to the sink, and starts over.
+func (d *Deduper) Flush() {
+	d.mu.Lock()
+	records := make([]DedupRecord, 0, len(d.groups))
+	for _, g := range d.groups {
+		if g.duplicates > 0 {
+			records = append(records, g.summary())
+		}
+	}
+	clear(d.groups)
+	d.mu.Unlock()
+	for _, r := range records {
+		d.sink(r)
+	}
+}
+
+func (d *Deduper) record(err error, site throw.Site) {
+	now := clock().Now()
+	key := dedupKey{site: site.Short(), key: ErrorCode(err)}
+	if key.key == "" {
+		key.key = err.Error()
+	}
+	d.mu.Lock()
+	records := d.evict(now)
+	if g := d.groups[key]; g != nil && now.Sub(g.first.First) < d.window {
+		if g.duplicates == 0 {
+			g.firstRepeat = now
+		}
+		g.duplicates++
+		g.last = now
+	} else {
+		if g != nil && g.duplicates > 0 {
+			records = append(records, g.summary())
+		}
+		g = &dedupGroup{first: DedupRecord{Err: err, Site: key.site, Count: 1, First: now, Last: now}}
+		d.groups[key] = g
+		records = append(records, g.first)
+	}
+	d.mu.Unlock()
+	for _, r := range records {
+		d.sink(r)
+	}
+}
+
+// evict removes the groups whose window has ended, at most once per window, so errors keyed
+// by messages that differ every time do not grow the groups without bound. It returns the
+// summaries of the removed groups. The caller holds d.mu.
+func (d *Deduper) evict(now time.Time) []DedupRecord {
+	if now.Sub(d.swept) < d.window {
+		return nil
+	}
+	d.swept = now
+	var records []DedupRecord
+	for key, g := range d.groups {
+		if now.Sub(g.first.First) < d.window {
+			continue
+		}
+		if g.duplicates > 0 {
+			records = append(records, g.summary())
+		}
+		delete(d.groups, key)
+	}
+	return records
+}
+
+func (g *dedupGroup) summary() DedupRecord {
+	r := g.first
+	r.Count, r.Summary, r.First, r.Last = g.duplicates, true, g.firstRepeat, g.last
+	return r
+}
@@ -0,0 +1,119 @@
+//go:build test
+
+package errless_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	e "github.com/mfatihercik/errless"
+	"github.com/mfatihercik/errless/errlesstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errFlood = errors.New("connection reset")
+
+type recordSink struct{ records []e.DedupRecord }
+
+func (s *recordSink) add(r e.DedupRecord) { s.records = append(s.records, r) }
+
+func TestDeduper(t *testing.T) {
+	t.Run("should pass the first error and count duplicates", func(t *testing.T) {
+		clock := errlesstest.UseFakeClock(t)
+		sink := &recordSink{}
+		d := e.NewDeduper(time.Minute, sink.add)
+		for i := 0; i < 5; i++ {
+			errlesstest.CaptureThrow(func() { e.Try(errFlood).Err(d.Handler) })
+			clock.Advance(time.Second)
+		}
+		require.Len(t, sink.records, 1)
+		first := sink.records[0]
+		assert.Equal(t, errFlood, first.Err)
+		assert.Equal(t, 1, first.Count)
+		assert.False(t, first.Summary)
+		assert.Regexp(t, `/dedup_test.go:\d+$`, first.Site)
+
+		d.Flush()
+		require.Len(t, sink.records, 2)
+		summary := sink.records[1]
+		assert.True(t, summary.Summary)
+		assert.Equal(t, 4, summary.Count)
+		assert.Equal(t, first.First.Add(time.Second), summary.First)
+		assert.Equal(t, first.First.Add(4*time.Second), summary.Last)
+	})
+	t.Run("should summarize when the window ends", func(t *testing.T) {
+		clock := errlesstest.UseFakeClock(t)
+		sink := &recordSink{}
+		d := e.NewDeduper(time.Minute, sink.add)
+		throw := func() { errlesstest.CaptureThrow(func() { e.Try(errFlood).Err(d.Handler) }) }
+		throw()
+		throw()
+		clock.Advance(time.Minute)
+		throw()
+		require.Len(t, sink.records, 3)
+		assert.Equal(t, 1, sink.records[1].Count)
+		assert.True(t, sink.records[1].Summary)
+		assert.False(t, sink.records[2].Summary)
+	})
+	t.Run("should flush the Deduper of the Dedup handler", func(t *testing.T) {
+		errlesstest.UseFakeClock(t)
+		sink := &recordSink{}
+		handle, flush := e.Dedup(time.Minute, sink.add)
+		for i := 0; i < 3; i++ {
+			errlesstest.CaptureThrow(func() { e.Try(errFlood).Err(handle) })
+		}
+		require.Len(t, sink.records, 1)
+		flush()
+		require.Len(t, sink.records, 2)
+		assert.True(t, sink.records[1].Summary)
+		assert.Equal(t, 2, sink.records[1].Count)
+	})
+	t.Run("should evict the groups of ended windows", func(t *testing.T) {
+		clock := errlesstest.UseFakeClock(t)
+		sink := &recordSink{}
+		d := e.NewDeduper(time.Minute, sink.add)
+		for i := 0; i < 2; i++ {
+			errlesstest.CaptureThrow(func() { e.Try(errFlood).Err(d.Handler) })
+		}
+		clock.Advance(time.Minute)
+		errlesstest.CaptureThrow(func() { e.Try(codedError{"a"}).Err(d.Handler) })
+		require.Len(t, sink.records, 3)
+		assert.Equal(t, errFlood, sink.records[1].Err)
+		assert.True(t, sink.records[1].Summary)
+		assert.Equal(t, 1, sink.records[1].Count)
+		assert.Equal(t, "a", e.ErrorCode(sink.records[2].Err))
+
+		d.Flush()
+		assert.Len(t, sink.records, 3)
+	})
+	t.Run("should group by site and code", func(t *testing.T) {
+		errlesstest.UseFakeClock(t)
+		sink := &recordSink{}
+		d := e.NewDeduper(time.Minute, sink.add)
+		errlesstest.CaptureThrow(func() { e.Try(errFlood).Err(d.Handler) })
+		errlesstest.CaptureThrow(func() { e.Try(errFlood).Err(d.Handler) })
+		errlesstest.CaptureThrow(func() { e.Try(codedError{"a"}).Err(d.Handler) })
+		errlesstest.CaptureThrow(func() { e.Try(codedError{"b"}).Err(d.Handler) })
+		assert.Len(t, sink.records, 4)
+	})
+	t.Run("should record errors caught by HandleErr", func(t *testing.T) {
+		errlesstest.UseFakeClock(t)
+		sink := &recordSink{}
+		d := e.NewDeduper(time.Minute, sink.add)
+		load := func() (err error) {
+			defer d.HandleErr(&err)
+			e.Try(errFlood).Err()
+			return nil
+		}
+		for i := 0; i < 3; i++ {
+			assert.ErrorIs(t, load(), errFlood)
+		}
+		d.Flush()
+		require.Len(t, sink.records, 2)
+		assert.Equal(t, 2, sink.records[1].Count)
+		d.Flush()
+		assert.Len(t, sink.records, 2)
+	})
+}
@@ -0,0 +1,209 @@
+package errless
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// maxEncodeDepth bounds the chains ToJSON follows, in case an error wraps itself.
+const maxEncodeDepth = 64
+
+// errorJSON is the JSON form of an error and the errors it wraps.
+type errorJSON struct {
+	Message       string         `json:"message"`
+	Type          string         `json:"type,omitempty"`
+	Code          string         `json:"code,omitempty"`
+	Sentinel      string         `json:"sentinel,omitempty"`
+	Fields        map[string]any `json:"fields,omitempty"`
+	Class         string         `json:"class,omitempty"`
+	PublicMessage string         `json:"public_message,omitempty"`
+	Key           string         `json:"key,omitempty"`
+	Args          []any          `json:"args,omitempty"`
+	Trail         Breadcrumbs    `json:"trail,omitempty"`
+	Stack         []Frame        `json:"stack,omitempty"`
+	Cause         *errorJSON     `json:"cause,omitempty"`
+	Causes        []*errorJSON   `json:"causes,omitempty"`
+}
+
+// Classes of the errors in the JSON form.
+const (
+	classNameUser      = "user"
+	classNameInternal  = "internal"
+	classNameRetryable = "retryable"
+)
+
+// ToJSON encodes err and the errors it wraps, following both Unwrap() error and
+// Unwrap() []error. Each error is written with its message, its code and fields (see
+// ErrorCode and ErrorFields), its sentinel name (see RegisterSentinel), its classification
+// and public message, its localization key, and the trail and stack frames of the errors
+// thrown while trails are recorded and stacks are captured (see SetTrail and SetStacks).
+func ToJSON(err error) ([]byte, error) {
+	return json.Marshal(encodeError(err, 0))
+}
+
+// FromJSON decodes an error encoded by ToJSON. The errors of errless are rebuilt with their
+// types, so classification, public messages and localization keep working. Other errors are
+// rebuilt with their message, code and fields; they match the errors with the same code and
+// the sentinel registered with their name (see RegisterSentinel) with errors.Is, and can be
+// filtered with IfCode.
+func FromJSON(data []byte) (error, error) {
+	var e *errorJSON
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("errless: decode error: %w", err)
+	}
+	return decodeError(e), nil
+}
+
+// ErrorFields returns the first fields in the chain of err that are returned by a
+// Fields() map[string]any method and are not empty, or nil.
+func ErrorFields(err error) map[string]any {
+	var fields map[string]any
+	walk(err, func(err error) bool {
+		if f, ok := err.(interface{ Fields() map[string]any }); ok {
+			fields = f.Fields()
+		}
+		return len(fields) > 0
+	})
+	return fields
+}
+
+// IfCode returns a filter that matches errors with an error of the given code in their chain.
+func IfCode(code string) IfFunc {
+	return func(err error) bool {
+		return walk(err, func(err error) bool {
+			c, ok := err.(interface{ Code() string })
+			return ok && c.Code() == code
+		})
+	}
+}
+
+func encodeError(err error, depth int) *errorJSON {
+	if err == nil {
+		return nil
+	}
+	e := &errorJSON{Message: err.Error(), Type: fmt.Sprintf("%T", err), Sentinel: sentinelNameOf(err)}
+	if c, ok := err.(interface{ Code() string }); ok {
+		e.Code = c.Code()
+	}
+	if f, ok := err.(interface{ Fields() map[string]any }); ok {
+		e.Fields = f.Fields()
+	}
+	switch v := err.(type) {
+	case *PublicError:
+		e.Class, e.PublicMessage = classNameUser, v.Message
+	case *classifiedError:
+		e.Class = classNameInternal
+		if v.class == classRetryable {
+			e.Class = classNameRetryable
+		}
+	case *LocalizedError:
+		e.Key, e.Args = v.Key, v.Args
+	case *trailError:
+		e.Trail = v.trail
+	case *stackError:
+		e.Stack = v.frames
+	case *remoteError:
+		e.Type = v.typ
+	case *remoteJoinError:
+		e.Type = v.typ
+	}
+	if depth == maxEncodeDepth {
+		return e
+	}
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		e.Cause = encodeError(u.Unwrap(), depth+1)
+	case interface{ Unwrap() []error }:
+		for _, cause := range u.Unwrap() {
+			e.Causes = append(e.Causes, encodeError(cause, depth+1))
+		}
+	}
+	return e
+}
+
+func decodeError(e *errorJSON) error {
+	if e == nil {
+		return nil
+	}
+	cause := decodeError(e.Cause)
+	switch {
+	case e.Class == classNameUser:
+		return &PublicError{Err: cause, Message: e.PublicMessage}
+	case e.Class == classNameInternal && cause != nil:
+		return &classifiedError{err: cause, class: classInternal}
+	case e.Class == classNameRetryable && cause != nil:
+		return &classifiedError{err: cause, class: classRetryable}
+	case e.Key != "":
+		return &LocalizedError{Err: cause, Key: e.Key, Args: e.Args}
+	case e.Trail != nil && cause != nil:
+		return &trailError{err: cause, trail: e.Trail}
+	case e.Stack != nil && cause != nil:
+		return &stackError{err: cause, frames: e.Stack}
+	}
+	r := remote{message: e.Message, typ: e.Type, code: e.Code, sentinel: e.Sentinel, fields: e.Fields}
+	if len(e.Causes) > 0 {
+		causes := make([]error, 0, len(e.Causes))
+		for _, c := range e.Causes {
+			if c := decodeError(c); c != nil {
+				causes = append(causes, c)
+			}
+		}
+		return &remoteJoinError{remote: r, causes: causes}
+	}
+	return &remoteError{remote: r, cause: cause}
+}
+
+// remote holds what is known of an error decoded by FromJSON.
+type remote struct {
+	message  string
+	typ      string // Go type of the encoded error
+	code     string
+	sentinel string // name of the registered sentinel the encoded error was
+	fields   map[string]any
+}
+
+func (r *remote) Error() string          { return r.message }
+func (r *remote) Code() string           { return r.code }
+func (r *remote) Fields() map[string]any { return r.fields }
+
+// Is matches the sentinel registered under the name the error was sent with, and the
+// errors with the same code.
+func (r *remote) Is(target error) bool {
+	if r.sentinel != "" {
+		if sentinel, ok := Sentinel(r.sentinel); ok && sentinel == target {
+			return true
+		}
+	}
+	if r.code == "" {
+		return false
+	}
+	c, ok := target.(interface{ Code() string })
+	return ok && c.Code() == r.code
+}
+
+// remoteError is a decoded error that wraps at most one error.
+type remoteError struct {
+	remote
+	cause error
+}
+
+func (e *remoteError) Unwrap() error                { return e.cause }
+func (e *remoteError) MarshalJSON() ([]byte, error) { return ToJSON(e) }
+
+// remoteJoinError is a decoded error that wraps several errors.
+type remoteJoinError struct {
+	remote
+	causes []error
+}
+
+func (e *remoteJoinError) Unwrap() []error              { return e.causes }
+func (e *remoteJoinError) MarshalJSON() ([]byte, error) { return ToJSON(e) }
+
+// MarshalJSON encodes the error with ToJSON.
+func (e *PublicError) MarshalJSON() ([]byte, error) { return ToJSON(e) }
+
+// MarshalJSON encodes the error with ToJSON.
+func (e *LocalizedError) MarshalJSON() ([]byte, error) { return ToJSON(e) }
+
+func (e *classifiedError) MarshalJSON() ([]byte, error) { return ToJSON(e) }
+func (e *trailError) MarshalJSON() ([]byte, error)      { return ToJSON(e) }
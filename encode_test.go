@@ -0,0 +1,117 @@
+//go:build test
+
+package errless_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	e "github.com/mfatihercik/errless"
+	"github.com/mfatihercik/errless/errlesstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fieldError has a code and fields, like the domain errors of an application.
+type fieldError struct {
+	codedError
+	id string
+}
+
+func (f fieldError) Fields() map[string]any { return map[string]any{"id": f.id} }
+
+func roundTrip(t *testing.T, err error) error {
+	t.Helper()
+	data, encodeErr := e.ToJSON(err)
+	require.NoError(t, encodeErr)
+	decoded, decodeErr := e.FromJSON(data)
+	require.NoError(t, decodeErr)
+	return decoded
+}
+
+func TestToJSON(t *testing.T) {
+	t.Run("should encode the chain", func(t *testing.T) {
+		err := e.Public("account not found")(fmt.Errorf("load: %w", fieldError{codedError{"not_found"}, "42"}))
+		data, encodeErr := e.ToJSON(err)
+		require.NoError(t, encodeErr)
+		assert.JSONEq(t, `{
+			"message": "load: coded not_found",
+			"type": "*errless.PublicError",
+			"class": "user",
+			"public_message": "account not found",
+			"cause": {
+				"message": "load: coded not_found",
+				"type": "*fmt.wrapError",
+				"cause": {
+					"message": "coded not_found",
+					"type": "errless_test.fieldError",
+					"code": "not_found",
+					"fields": {"id": "42"}
+				}
+			}
+		}`, string(data))
+	})
+	t.Run("should encode joined errors", func(t *testing.T) {
+		data, encodeErr := e.ToJSON(multiError{errDB, codedError{"io"}})
+		require.NoError(t, encodeErr)
+		var decoded map[string]any
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Len(t, decoded["causes"], 2)
+	})
+	t.Run("should encode the trail", func(t *testing.T) {
+		defer e.SetTrail(true)()
+		err := errlesstest.CaptureThrow(func() { e.Try(errDB).Err() })
+		data, encodeErr := json.Marshal(err)
+		require.NoError(t, encodeErr)
+		assert.Contains(t, string(data), `"trail":[{"kind":"thrown","function":"github.com/mfatihercik/errless_test.`)
+	})
+	t.Run("should encode nil", func(t *testing.T) {
+		data, encodeErr := e.ToJSON(nil)
+		require.NoError(t, encodeErr)
+		assert.Equal(t, "null", string(data))
+	})
+}
+
+func TestFromJSON(t *testing.T) {
+	t.Run("should rebuild codes and fields", func(t *testing.T) {
+		err := roundTrip(t, fmt.Errorf("load: %w", fieldError{codedError{"not_found"}, "42"}))
+		assert.EqualError(t, err, "load: coded not_found")
+		assert.Equal(t, "not_found", e.ErrorCode(err))
+		assert.Equal(t, map[string]any{"id": "42"}, e.ErrorFields(err))
+		assert.True(t, e.IfCode("not_found")(err))
+		assert.False(t, e.IfCode("conflict")(err))
+		assert.ErrorIs(t, err, codedError{"not_found"})
+		assert.NotErrorIs(t, err, codedError{"conflict"})
+	})
+	t.Run("should rebuild the errors of errless", func(t *testing.T) {
+		err := e.AsRetryable()(e.Public("busy")(e.Localized("db.busy", "orders")(errDB)))
+		decoded := roundTrip(t, err)
+		assert.EqualError(t, decoded, err.Error())
+		assert.True(t, e.IsRetryable(decoded))
+		assert.Equal(t, "busy", e.PublicMessageOf(decoded))
+		var localized *e.LocalizedError
+		require.ErrorAs(t, decoded, &localized)
+		assert.Equal(t, "db.busy", localized.Key)
+		assert.Equal(t, []any{"orders"}, localized.Args)
+	})
+	t.Run("should rebuild joined errors", func(t *testing.T) {
+		err := roundTrip(t, multiError{errDB, codedError{"io"}})
+		assert.True(t, e.IfCode("io")(err))
+		assert.Equal(t, "multiple errors", err.Error())
+	})
+	t.Run("should rebuild the trail", func(t *testing.T) {
+		defer e.SetTrail(true)()
+		err := errlesstest.CaptureThrow(func() { e.Try(errDB).Err() })
+		assert.Equal(t, e.Trail(err), e.Trail(roundTrip(t, err)))
+	})
+	t.Run("should report invalid data", func(t *testing.T) {
+		_, err := e.FromJSON([]byte("{"))
+		assert.Error(t, err)
+	})
+	t.Run("should decode null as nil", func(t *testing.T) {
+		err, decodeErr := e.FromJSON([]byte("null"))
+		assert.NoError(t, decodeErr)
+		assert.Nil(t, err)
+	})
+}
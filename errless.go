@@ -4,11 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-)
 
-type exception struct {
-	error error
-}
+	"github.com/mfatihercik/errless/internal/throw"
+)
 
 // HandlerFunc defines the signature for an error handler.
 type HandlerFunc func(error) error
@@ -55,23 +53,18 @@ func EmptyHandler(err error) error {
 // Error handler functions
 // --------------------------
 
-func recoverException(r any) *exception {
-	if r != nil {
-		if e, ok := r.(exception); ok {
-			return &e
-		} else {
-			// This was not an error panic; re-panic with the original value.
-			panic(r)
-		}
-	}
-	return nil
+func recoverException(r any) *throw.Exception {
+	// Panics that were not raised by Throw are re-panicked with the original value.
+	return throw.Recover(r)
 }
 
 // HandleErr is set caught error to passed named error
 func HandleErr(namedErr *error) {
 	exp := recoverException(recover())
 	if exp != nil && namedErr != nil {
-		*namedErr = exp.error
+		recoverTrail(exp, "errless.HandleErr")
+		observeRecover(exp, exp.Err)
+		*namedErr = exp.Err
 
 	}
 }
@@ -80,9 +73,61 @@ func HandleErr(namedErr *error) {
 func Handle(namedErr *error, onError func(error) error) {
 	exp := recoverException(recover())
 	if exp != nil {
-		e := onError(exp.error) // Use the provided custom error handling logic.
+		t := recoverTrail(exp, "errless.Handle")
+		t.handled(onError)
+		e := onError(exp.Err) // Use the provided custom error handling logic.
+		observeRecover(exp, e)
+		if namedErr != nil {
+			*namedErr = t.attach(e)
+		}
+	}
+}
+
+// HandleResult is used like Handle for functions with a named result besides the error.
+// onError returns the values to set both results to, so a partially built result does not reach the caller.
+func HandleResult[T any](namedRes *T, namedErr *error, onError func(error) (T, error)) {
+	exp := recoverException(recover())
+	if exp != nil {
+		t := recoverTrail(exp, "errless.HandleResult")
+		t.handled(onError)
+		res, e := onError(exp.Err)
+		observeRecover(exp, e)
+		if namedRes != nil {
+			*namedRes = res
+		}
 		if namedErr != nil {
-			*namedErr = e
+			*namedErr = t.attach(e)
+		}
+	}
+}
+
+// HandleZero is used like HandleErr, and also resets the named result to its zero value.
+func HandleZero[T any](namedRes *T, namedErr *error) {
+	exp := recoverException(recover())
+	if exp != nil {
+		recoverTrail(exp, "errless.HandleZero")
+		observeRecover(exp, exp.Err)
+		if namedRes != nil {
+			var zero T
+			*namedRes = zero
+		}
+		if namedErr != nil {
+			*namedErr = exp.Err
+		}
+	}
+}
+
+// HandleWithDefault is used like HandleErr, and also sets the named result to value.
+func HandleWithDefault[T any](namedRes *T, namedErr *error, value T) {
+	exp := recoverException(recover())
+	if exp != nil {
+		recoverTrail(exp, "errless.HandleWithDefault")
+		observeRecover(exp, exp.Err)
+		if namedRes != nil {
+			*namedRes = value
+		}
+		if namedErr != nil {
+			*namedErr = exp.Err
 		}
 	}
 }
@@ -90,57 +135,161 @@ func Handle(namedErr *error, onError func(error) error) {
 func Catch(onError func(e error)) {
 	exp := recoverException(recover())
 	if exp != nil {
-		onError(exp.error)
+		recoverTrail(exp, "errless.Catch").handled(onError)
+		observeRecover(exp, nil)
+		onError(exp.Err)
 	}
 }
 
+// IsThrown reports whether a value returned by recover was thrown by errless, and returns its error.
+// It has no side effects: unlike the Handle functions, it does not record the recovery in the trail
+// or the metrics, so it can be called more than once for the same value.
+// It lets recover based code of other libraries turn errless panics into errors:
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			if err, ok := errless.IsThrown(r); ok {
+//				http.Error(w, errless.PublicMessageOf(err), http.StatusInternalServerError)
+//				return
+//			}
+//			panic(r)
+//		}
+//	}()
+func IsThrown(recovered any) (error, bool) {
+	exp, ok := recovered.(throw.Exception)
+	if !ok {
+		return nil, false
+	}
+	return exp.Err, true
+}
+
+// Rethrow throws an error that was already thrown and recovered, such as one returned by IsThrown,
+// without applying the handlers again.
+func Rethrow(err error) {
+	rethrow(err)
+}
+
 // zero parameter functions
 // --------------------------
 
 // Throw checks the error with default error handler.
+// The handlers of the call site run first, followed by the handlers registered
+// for the calling package and the default handlers.
 func Throw(err error, handles ...HandlerFunc) {
 	if err != nil {
-		for _, handle := range handles {
-			err = handle(err)
-			if err == nil {
-				// get nil error no need to call the rest of the handler
-				break
-			}
+		if exp, thrown := raise(err, handles, false); thrown {
+			panic(exp)
+		}
+	}
+}
+
+// raise applies the handlers of Throw to err, and returns the exception to throw for it,
+// or false if a handler replaced the error with nil. When rethrown is set, err was thrown
+// before, so the registered handlers are not applied and the metrics are not observed again.
+func raise(err error, handles []HandlerFunc, rethrown bool) (throw.Exception, bool) {
+	t := startTrail(err, Breadcrumb{Kind: Thrown})
+	handled := applyHandlers(err, handles, t)
+	if handled == nil {
+		if !rethrown {
+			observeHandled(err)
 		}
-		if err != nil {
-			panic(exception{error: err})
+		return throw.Exception{}, false
+	}
+	site := throw.Caller()
+	err = handled
+	if !rethrown {
+		err = applyRegistered(handled, site, t)
+		if err == nil {
+			observeError(handled, site, OutcomeHandled)
+			return throw.Exception{}, false
+		}
+		observeError(err, site, OutcomeThrown)
+	}
+	t.at(site)
+	stack := throw.Stack(stacksEnabled.Load())
+	err = t.attach(attachStack(err, stack))
+	return throw.Exception{Err: err, Site: site, Time: thrownAt(), Stack: stack}, true
+}
+
+// rethrow throws err, which was thrown before, with the handles but not the registered handlers.
+func rethrow(err error, handles ...HandlerFunc) {
+	if err != nil {
+		if exp, thrown := raise(err, handles, true); thrown {
+			panic(exp)
+		}
+	}
+}
+
+func applyHandlers(err error, handles []HandlerFunc, t *trailRecorder) error {
+	for _, handle := range handles {
+		t.handled(handle)
+		err = handle(err)
+		if err == nil {
+			// get nil error no need to call the rest of the handler
+			break
 		}
 	}
+	return err
 }
 
 // Params0 hold function parameters and error.
+// ParamsN values are returned by value, so checking a successful call does not allocate,
+// unless a handler passed to Err is a closure over local variables, which goes to the heap.
 type Params0 struct {
 	err          error
+	scope        *Scope
+	thrown       bool // err was thrown before, see raise
 	skipNextStep bool
 }
 
-func Try(err error) *Params0 {
-	return &Params0{err: err}
+func Try(err error) Params0 {
+	return Params0{err: injectAtSite(err)}
 }
 
-func (r *Params0) Err(handle ...HandlerFunc) {
+func (r Params0) Err(handle ...HandlerFunc) {
 	if !r.skipNextStep {
-		Throw(r.err, handle...)
+		r.scope.check(r.err, handle, r.thrown)
 	}
 }
-func (r *Params0) Or(handle func(error)) {
+func (r Params0) Or(handle func(error)) {
+	observeFallback(r.err)
 	handle(r.err)
 }
-func (r *Params0) If(handle ...IfFunc) *Params0 {
+func (r Params0) If(handle ...IfFunc) Params0 {
 	r.skipNextStep = !applyNextStep(handle, r.err, r.skipNextStep)
 	return r
 }
 
-func (r *Params0) ErrMessage(message string) {
-	r.Err(Message(message))
+// Label names the call site, so tests built with the errless_fault tag can inject an error for it.
+func (r Params0) Label(label string) Params0 {
+	r.err = injectAtLabel(label, r.err)
+	return r
+}
+
+// In records the error in s instead of throwing it, see Scope.
+func (r Params0) In(s *Scope) Params0 {
+	r.scope = s
+	return r
 }
-func (r *Params0) ErrWrap(message string) {
-	r.Err(Wrap(message))
+
+// ErrMessage throws the error with message, see Message. Like ErrWrap and ErrPublic, it only
+// builds the handler for a failed call, so that a successful one does not allocate it.
+func (r Params0) ErrMessage(message string) {
+	if r.err != nil {
+		r.Err(Message(message))
+	}
+}
+func (r Params0) ErrWrap(message string) {
+	if r.err != nil {
+		r.Err(Wrap(message))
+	}
+}
+
+// ErrPublic throws the error wrapped in a PublicError with message, see Public.
+func (r Params0) ErrPublic(message string) {
+	if r.err != nil {
+		r.Err(Public(message))
+	}
 }
 
 // one parameter functions
@@ -156,49 +305,81 @@ func Throw1[A any](a A, err error) A {
 type Params1[A any] struct {
 	paramA         A
 	err            error
+	scope          *Scope
+	thrown         bool // err was thrown before, see raise
 	skipNextHandle bool
 }
 
-func Try1[A any](a A, err error) *Params1[A] {
-	return &Params1[A]{paramA: a, err: err}
+func Try1[A any](a A, err error) Params1[A] {
+	return Params1[A]{paramA: a, err: injectAtSite(err)}
 }
 
 // Err applies an error handler to the Result.
-func (r *Params1[A]) Err(handle ...HandlerFunc) A {
+func (r Params1[A]) Err(handle ...HandlerFunc) A {
 	if !r.skipNextHandle {
-		Throw(r.err, handle...)
+		if r.scope.check(r.err, handle, r.thrown) {
+			r = Params1[A]{} // the error is recorded in the scope
+		}
 	}
 	return r.paramA
 }
 
-func (r *Params1[A]) Fallback(handle func(error) A) A {
-	if r.skipNextHandle {
-		Throw(r.err)
+func (r Params1[A]) Fallback(handle func(error) A) A {
+	if r.skipNextHandle && r.scope.check(r.err, nil, r.thrown) {
+		r = Params1[A]{} // the error is recorded in the scope
+		return r.paramA
 	}
+	observeFallback(r.err)
 	return handle(r.err)
 }
-func (r *Params1[A]) If(handle ...IfFunc) *Params1[A] {
+func (r Params1[A]) If(handle ...IfFunc) Params1[A] {
 	r.skipNextHandle = !applyNextStep(handle, r.err, r.skipNextHandle)
 	return r
 }
 
-func (r *Params1[A]) IfIs(err error) *Params1[A] {
+func (r Params1[A]) IfIs(err error) Params1[A] {
 	return r.If(Is(err))
 }
-func (r *Params1[A]) IfNot(err error) *Params1[A] {
+func (r Params1[A]) IfNot(err error) Params1[A] {
 	return r.If(IsNot(err))
 }
 
 // E is an alias for Err.
-func (r *Params1[A]) E(handle ...HandlerFunc) A {
+func (r Params1[A]) E(handle ...HandlerFunc) A {
 	return r.Err(handle...)
 }
 
-func (r *Params1[A]) ErrMessage(message string) A {
-	return r.Err(Message(message))
+// Label names the call site, so tests built with the errless_fault tag can inject an error for it.
+func (r Params1[A]) Label(label string) Params1[A] {
+	r.err = injectAtLabel(label, r.err)
+	return r
+}
+
+// In records the error in s instead of throwing it, see Scope.
+func (r Params1[A]) In(s *Scope) Params1[A] {
+	r.scope = s
+	return r
+}
+
+func (r Params1[A]) ErrMessage(message string) A {
+	if r.err != nil {
+		return r.Err(Message(message))
+	}
+	return r.Err()
 }
-func (r *Params1[A]) ErrWrap(message string) A {
-	return r.Err(Wrap(message))
+func (r Params1[A]) ErrWrap(message string) A {
+	if r.err != nil {
+		return r.Err(Wrap(message))
+	}
+	return r.Err()
+}
+
+// ErrPublic throws the error wrapped in a PublicError with message, see Public.
+func (r Params1[A]) ErrPublic(message string) A {
+	if r.err != nil {
+		return r.Err(Public(message))
+	}
+	return r.Err()
 }
 
 // 2 parameter functions
@@ -215,45 +396,76 @@ type Params2[A, B any] struct {
 	paramA       A
 	paramB       B
 	err          error
+	scope        *Scope
 	skipNextStep bool
 }
 
-func Try2[A, B any](a A, b B, err error) *Params2[A, B] {
-	return &Params2[A, B]{paramA: a, paramB: b, err: err}
+func Try2[A, B any](a A, b B, err error) Params2[A, B] {
+	return Params2[A, B]{paramA: a, paramB: b, err: injectAtSite(err)}
 }
 
 // Err  applies an error handler to the Result.
-func (r *Params2[A, B]) Err(handle ...HandlerFunc) (A, B) {
+func (r Params2[A, B]) Err(handle ...HandlerFunc) (A, B) {
 	if !r.skipNextStep {
-		Throw(r.err, handle...)
+		if r.scope.throw(r.err, handle...) {
+			r = Params2[A, B]{} // the error is recorded in the scope
+		}
 	}
 	return r.paramA, r.paramB
 }
 
-func (r *Params2[A, B]) Fallback(handle func(error) (A, B)) (A, B) {
-	if r.skipNextStep {
-		Throw(r.err)
+func (r Params2[A, B]) Fallback(handle func(error) (A, B)) (A, B) {
+	if r.skipNextStep && r.scope.throw(r.err) {
+		r = Params2[A, B]{} // the error is recorded in the scope
+		return r.paramA, r.paramB
 	}
+	observeFallback(r.err)
 	return handle(r.err)
 }
 
-func (r *Params2[A, B]) If(handle ...IfFunc) *Params2[A, B] {
+func (r Params2[A, B]) If(handle ...IfFunc) Params2[A, B] {
 	r.skipNextStep = !applyNextStep(handle, r.err, r.skipNextStep)
 	return r
 }
 
-func (r *Params2[A, B]) IfIs(err error) *Params2[A, B] {
+func (r Params2[A, B]) IfIs(err error) Params2[A, B] {
 	return r.If(Is(err))
 }
-func (r *Params2[A, B]) IfNot(err error) *Params2[A, B] {
+func (r Params2[A, B]) IfNot(err error) Params2[A, B] {
 	return r.If(IsNot(err))
 }
 
-func (r *Params2[A, B]) ErrMessage(message string) (A, B) {
-	return r.Err(Message(message))
+// Label names the call site, so tests built with the errless_fault tag can inject an error for it.
+func (r Params2[A, B]) Label(label string) Params2[A, B] {
+	r.err = injectAtLabel(label, r.err)
+	return r
 }
-func (r *Params2[A, B]) ErrWrap(message string) (A, B) {
-	return r.Err(Wrap(message))
+
+// In records the error in s instead of throwing it, see Scope.
+func (r Params2[A, B]) In(s *Scope) Params2[A, B] {
+	r.scope = s
+	return r
+}
+
+func (r Params2[A, B]) ErrMessage(message string) (A, B) {
+	if r.err != nil {
+		return r.Err(Message(message))
+	}
+	return r.Err()
+}
+func (r Params2[A, B]) ErrWrap(message string) (A, B) {
+	if r.err != nil {
+		return r.Err(Wrap(message))
+	}
+	return r.Err()
+}
+
+// ErrPublic throws the error wrapped in a PublicError with message, see Public.
+func (r Params2[A, B]) ErrPublic(message string) (A, B) {
+	if r.err != nil {
+		return r.Err(Public(message))
+	}
+	return r.Err()
 }
 
 // three parameter functions
@@ -271,45 +483,76 @@ type Params3[A, B, C any] struct {
 	paramB       B
 	paramC       C
 	err          error
+	scope        *Scope
 	skipNextStep bool
 }
 
-func Try3[A, B, C any](a A, b B, c C, err error) *Params3[A, B, C] {
-	return &Params3[A, B, C]{paramA: a, paramB: b, paramC: c, err: err}
+func Try3[A, B, C any](a A, b B, c C, err error) Params3[A, B, C] {
+	return Params3[A, B, C]{paramA: a, paramB: b, paramC: c, err: injectAtSite(err)}
 }
 
 // Err applies an error handler to the Result.
-func (r *Params3[A, B, C]) Err(handle ...HandlerFunc) (A, B, C) {
+func (r Params3[A, B, C]) Err(handle ...HandlerFunc) (A, B, C) {
 	if !r.skipNextStep {
-		Throw(r.err, handle...)
+		if r.scope.throw(r.err, handle...) {
+			r = Params3[A, B, C]{} // the error is recorded in the scope
+		}
 	}
 	return r.paramA, r.paramB, r.paramC
 }
 
-func (r *Params3[A, B, C]) Fallback(handle func(error) (A, B, C)) (A, B, C) {
-	if r.skipNextStep {
-		Throw(r.err)
+func (r Params3[A, B, C]) Fallback(handle func(error) (A, B, C)) (A, B, C) {
+	if r.skipNextStep && r.scope.throw(r.err) {
+		r = Params3[A, B, C]{} // the error is recorded in the scope
+		return r.paramA, r.paramB, r.paramC
 	}
+	observeFallback(r.err)
 	return handle(r.err)
 }
 
-func (r *Params3[A, B, C]) If(handle ...IfFunc) *Params3[A, B, C] {
+func (r Params3[A, B, C]) If(handle ...IfFunc) Params3[A, B, C] {
 	r.skipNextStep = !applyNextStep(handle, r.err, r.skipNextStep)
 	return r
 }
 
-func (r *Params3[A, B, C]) IfIs(err error) *Params3[A, B, C] {
+func (r Params3[A, B, C]) IfIs(err error) Params3[A, B, C] {
 	return r.If(Is(err))
 }
-func (r *Params3[A, B, C]) IfNot(err error) *Params3[A, B, C] {
+func (r Params3[A, B, C]) IfNot(err error) Params3[A, B, C] {
 	return r.If(IsNot(err))
 }
 
-func (r *Params3[A, B, C]) ErrMessage(message string) (A, B, C) {
-	return r.Err(Message(message))
+// Label names the call site, so tests built with the errless_fault tag can inject an error for it.
+func (r Params3[A, B, C]) Label(label string) Params3[A, B, C] {
+	r.err = injectAtLabel(label, r.err)
+	return r
 }
-func (r *Params3[A, B, C]) ErrWrap(message string) (A, B, C) {
-	return r.Err(Wrap(message))
+
+// In records the error in s instead of throwing it, see Scope.
+func (r Params3[A, B, C]) In(s *Scope) Params3[A, B, C] {
+	r.scope = s
+	return r
+}
+
+func (r Params3[A, B, C]) ErrMessage(message string) (A, B, C) {
+	if r.err != nil {
+		return r.Err(Message(message))
+	}
+	return r.Err()
+}
+func (r Params3[A, B, C]) ErrWrap(message string) (A, B, C) {
+	if r.err != nil {
+		return r.Err(Wrap(message))
+	}
+	return r.Err()
+}
+
+// ErrPublic throws the error wrapped in a PublicError with message, see Public.
+func (r Params3[A, B, C]) ErrPublic(message string) (A, B, C) {
+	if r.err != nil {
+		return r.Err(Public(message))
+	}
+	return r.Err()
 }
 
 // four parameter functions
@@ -328,45 +571,76 @@ type Params4[A, B, C, D any] struct {
 	paramC       C
 	paramD       D
 	err          error
+	scope        *Scope
 	skipNextStep bool
 }
 
-func Try4[A, B, C, D any](a A, b B, c C, d D, err error) *Params4[A, B, C, D] {
-	return &Params4[A, B, C, D]{paramA: a, paramB: b, paramC: c, paramD: d, err: err}
+func Try4[A, B, C, D any](a A, b B, c C, d D, err error) Params4[A, B, C, D] {
+	return Params4[A, B, C, D]{paramA: a, paramB: b, paramC: c, paramD: d, err: injectAtSite(err)}
 }
 
 // Err applies an error handler to the Result.
-func (r *Params4[A, B, C, D]) Err(handle ...HandlerFunc) (A, B, C, D) {
+func (r Params4[A, B, C, D]) Err(handle ...HandlerFunc) (A, B, C, D) {
 	if !r.skipNextStep {
-		Throw(r.err, handle...)
+		if r.scope.throw(r.err, handle...) {
+			r = Params4[A, B, C, D]{} // the error is recorded in the scope
+		}
 	}
 	return r.paramA, r.paramB, r.paramC, r.paramD
 }
 
-func (r *Params4[A, B, C, D]) Fallback(handle func(error) (A, B, C, D)) (A, B, C, D) {
-	if r.skipNextStep {
-		Throw(r.err)
+func (r Params4[A, B, C, D]) Fallback(handle func(error) (A, B, C, D)) (A, B, C, D) {
+	if r.skipNextStep && r.scope.throw(r.err) {
+		r = Params4[A, B, C, D]{} // the error is recorded in the scope
+		return r.paramA, r.paramB, r.paramC, r.paramD
 	}
+	observeFallback(r.err)
 	return handle(r.err)
 }
 
-func (r *Params4[A, B, C, D]) If(handle ...IfFunc) *Params4[A, B, C, D] {
+func (r Params4[A, B, C, D]) If(handle ...IfFunc) Params4[A, B, C, D] {
 	r.skipNextStep = !applyNextStep(handle, r.err, r.skipNextStep)
 	return r
 }
 
-func (r *Params4[A, B, C, D]) IfIs(err error) *Params4[A, B, C, D] {
+func (r Params4[A, B, C, D]) IfIs(err error) Params4[A, B, C, D] {
 	return r.If(Is(err))
 }
-func (r *Params4[A, B, C, D]) IfNot(err error) *Params4[A, B, C, D] {
+func (r Params4[A, B, C, D]) IfNot(err error) Params4[A, B, C, D] {
 	return r.If(IsNot(err))
 }
 
-func (r *Params4[A, B, C, D]) ErrMessage(message string) (A, B, C, D) {
-	return r.Err(Message(message))
+// Label names the call site, so tests built with the errless_fault tag can inject an error for it.
+func (r Params4[A, B, C, D]) Label(label string) Params4[A, B, C, D] {
+	r.err = injectAtLabel(label, r.err)
+	return r
+}
+
+// In records the error in s instead of throwing it, see Scope.
+func (r Params4[A, B, C, D]) In(s *Scope) Params4[A, B, C, D] {
+	r.scope = s
+	return r
+}
+
+func (r Params4[A, B, C, D]) ErrMessage(message string) (A, B, C, D) {
+	if r.err != nil {
+		return r.Err(Message(message))
+	}
+	return r.Err()
+}
+func (r Params4[A, B, C, D]) ErrWrap(message string) (A, B, C, D) {
+	if r.err != nil {
+		return r.Err(Wrap(message))
+	}
+	return r.Err()
 }
-func (r *Params4[A, B, C, D]) ErrWrap(message string) (A, B, C, D) {
-	return r.Err(Wrap(message))
+
+// ErrPublic throws the error wrapped in a PublicError with message, see Public.
+func (r Params4[A, B, C, D]) ErrPublic(message string) (A, B, C, D) {
+	if r.err != nil {
+		return r.Err(Public(message))
+	}
+	return r.Err()
 }
 
 // five parameter functions
@@ -386,39 +660,41 @@ type Params5[A, B, C, D, E any] struct {
 	paramD       D
 	paramE       E
 	err          error
+	scope        *Scope
 	skipNextStep bool
 }
 
-func Try5[A, B, C, D, E any](a A, b B, c C, d D, e E, err error) *Params5[A, B, C, D, E] {
-	return &Params5[A, B, C, D, E]{paramA: a, paramB: b, paramC: c, paramD: d, paramE: e, err: err}
+func Try5[A, B, C, D, E any](a A, b B, c C, d D, e E, err error) Params5[A, B, C, D, E] {
+	return Params5[A, B, C, D, E]{paramA: a, paramB: b, paramC: c, paramD: d, paramE: e, err: injectAtSite(err)}
 }
 
 // Err applies an error handler to the Result.
-func (r *Params5[A, B, C, D, E]) Err(handle ...HandlerFunc) (A, B, C, D, E) {
+func (r Params5[A, B, C, D, E]) Err(handle ...HandlerFunc) (A, B, C, D, E) {
 	if !r.skipNextStep {
-		Throw(r.err, handle...)
+		if r.scope.throw(r.err, handle...) {
+			r = Params5[A, B, C, D, E]{} // the error is recorded in the scope
+		}
 	}
 	return r.paramA, r.paramB, r.paramC, r.paramD, r.paramE
 }
-func (r *Params5[A, B, C, D, E]) Fallback(handle func(error) (A, B, C, D, E)) (A, B, C, D, E) {
-	if r.skipNextStep {
-		Throw(r.err)
-	}
-	if r.skipNextStep {
-		Throw(r.err)
+func (r Params5[A, B, C, D, E]) Fallback(handle func(error) (A, B, C, D, E)) (A, B, C, D, E) {
+	if r.skipNextStep && r.scope.throw(r.err) {
+		r = Params5[A, B, C, D, E]{} // the error is recorded in the scope
+		return r.paramA, r.paramB, r.paramC, r.paramD, r.paramE
 	}
+	observeFallback(r.err)
 	return handle(r.err)
 }
 
-func (r *Params5[A, B, C, D, E]) If(handle ...IfFunc) *Params5[A, B, C, D, E] {
+func (r Params5[A, B, C, D, E]) If(handle ...IfFunc) Params5[A, B, C, D, E] {
 	r.skipNextStep = !applyNextStep(handle, r.err, r.skipNextStep)
 	return r
 }
 
-func (r *Params5[A, B, C, D, E]) IfIs(err error) *Params5[A, B, C, D, E] {
+func (r Params5[A, B, C, D, E]) IfIs(err error) Params5[A, B, C, D, E] {
 	return r.If(Is(err))
 }
-func (r *Params5[A, B, C, D, E]) IfNot(err error) *Params5[A, B, C, D, E] {
+func (r Params5[A, B, C, D, E]) IfNot(err error) Params5[A, B, C, D, E] {
 	return r.If(IsNot(err))
 }
 
@@ -438,9 +714,35 @@ func applyNextStep(handle []IfFunc, err error, skipNextStep bool) bool {
 	return applyNext && apply
 }
 
-func (r *Params5[A, B, C, D, E]) ErrMessage(message string) (A, B, C, D, E) {
-	return r.Err(Message(message))
+// Label names the call site, so tests built with the errless_fault tag can inject an error for it.
+func (r Params5[A, B, C, D, E]) Label(label string) Params5[A, B, C, D, E] {
+	r.err = injectAtLabel(label, r.err)
+	return r
+}
+
+// In records the error in s instead of throwing it, see Scope.
+func (r Params5[A, B, C, D, E]) In(s *Scope) Params5[A, B, C, D, E] {
+	r.scope = s
+	return r
 }
-func (r *Params5[A, B, C, D, E]) ErrWrap(message string) (A, B, C, D, E) {
-	return r.Err(Wrap(message))
+
+func (r Params5[A, B, C, D, E]) ErrMessage(message string) (A, B, C, D, E) {
+	if r.err != nil {
+		return r.Err(Message(message))
+	}
+	return r.Err()
+}
+func (r Params5[A, B, C, D, E]) ErrWrap(message string) (A, B, C, D, E) {
+	if r.err != nil {
+		return r.Err(Wrap(message))
+	}
+	return r.Err()
+}
+
+// ErrPublic throws the error wrapped in a PublicError with message, see Public.
+func (r Params5[A, B, C, D, E]) ErrPublic(message string) (A, B, C, D, E) {
+	if r.err != nil {
+		return r.Err(Public(message))
+	}
+	return r.Err()
 }
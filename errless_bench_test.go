@@ -0,0 +1,89 @@
+//go:build test
+
+package errless_test
+
+import (
+	"strconv"
+	"testing"
+
+	e "github.com/mfatihercik/errless"
+	"github.com/stretchr/testify/assert"
+)
+
+var sink int
+
+func plainSum(a, b string) (int, error) {
+	x, err := strconv.Atoi(a)
+	if err != nil {
+		return 0, err
+	}
+	y, err := strconv.Atoi(b)
+	if err != nil {
+		return 0, err
+	}
+	return x + y, nil
+}
+
+func throwSum(a, b string) (res int, err error) {
+	defer e.HandleErr(&err)
+	return e.Throw1(strconv.Atoi(a)) + e.Throw1(strconv.Atoi(b)), nil
+}
+
+func trySum(a, b string) (res int, err error) {
+	defer e.HandleErr(&err)
+	return e.Try1(strconv.Atoi(a)).Err() + e.Try1(strconv.Atoi(b)).Err(), nil
+}
+
+func tryMessageSum(a, b string) (res int, err error) {
+	defer e.HandleErr(&err)
+	x := e.Try1(strconv.Atoi(a)).ErrMessage("parse a")
+	y := e.Try1(strconv.Atoi(b)).IfNot(strconv.ErrRange).ErrMessage("parse b")
+	return x + y, nil
+}
+
+var sums = map[string]func(a, b string) (int, error){
+	"Plain":            plainSum,
+	"Throw1":           throwSum,
+	"Try1Err":          trySum,
+	"Try1IfErrMessage": tryMessageSum,
+}
+
+func BenchmarkSuccessPath(b *testing.B) {
+	for name, sum := range sums {
+		sum := sum
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				sink, _ = sum("10", "20")
+			}
+		})
+	}
+}
+
+func BenchmarkErrorPath(b *testing.B) {
+	for name, sum := range sums {
+		sum := sum
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				sink, _ = sum("10", "x")
+			}
+		})
+	}
+}
+
+func TestSuccessPathDoesNotAllocate(t *testing.T) {
+	for name, sum := range sums {
+		allocs := testing.AllocsPerRun(100, func() {
+			sink, _ = sum("10", "20")
+		})
+		assert.Zero(t, allocs, name)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		e.Try(nil).If(e.IsNot(strconv.ErrRange)).Err()
+		sink, _, _, _ = e.Try4(1, 2, 3, 4, nil).ErrWrap("four")
+		sink, _, _, _, _ = e.Try5(1, 2, 3, 4, 5, nil).Err()
+	})
+	assert.Zero(t, allocs, "Try chains")
+}
@@ -449,3 +449,103 @@ func TestHandleFunctions(t *testing.T) {
 	})
 
 }
+
+func recoverForeign(fn func()) (err error, thrown bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			err, thrown = e.IsThrown(r)
+		}
+	}()
+	fn()
+	return nil, false
+}
+
+func TestIsThrown(t *testing.T) {
+	t.Run("should return the error of an errless panic", func(t *testing.T) {
+		err, thrown := recoverForeign(func() { e.Try(errors.New("closed")).ErrMessage("read") })
+		assert.True(t, thrown)
+		assert.EqualError(t, err, "read - error: closed")
+	})
+	t.Run("should tell other panics apart", func(t *testing.T) {
+		err, thrown := recoverForeign(func() { panic("boom") })
+		assert.False(t, thrown)
+		assert.NoError(t, err)
+	})
+	t.Run("should not record the recovery", func(t *testing.T) {
+		m := observe(t)
+		_, thrown := recoverForeign(func() { e.Try(errClosed).Err() })
+		assert.True(t, thrown)
+		if assert.Len(t, m.counters, 1) {
+			assert.Equal(t, e.OutcomeThrown, m.counters[0].labels.Outcome)
+		}
+	})
+	t.Run("should rethrow the error without the handlers", func(t *testing.T) {
+		err, _ := recoverForeign(func() { e.Try(errClosed).ErrMessage("read") })
+		rethrown := func() (caught error) {
+			defer e.HandleErr(&caught)
+			e.Rethrow(err)
+			return nil
+		}
+		assert.Same(t, err, rethrown())
+		assert.NotPanics(t, func() { e.Rethrow(nil) })
+	})
+}
+
+type account struct {
+	ID    int
+	Owner string
+}
+
+// fillAccount sets the fields of res one by one, and throws before the owner when fail is set.
+func fillAccount(res *account, fail bool) {
+	res.ID = 7
+	if fail {
+		e.Try(errors.New("closed")).ErrMessage("load owner")
+	}
+	res.Owner = "ada"
+}
+
+func loadAccountResult(fail bool) (res account, err error) {
+	defer e.HandleResult(&res, &err, func(err error) (account, error) {
+		return account{Owner: "guest"}, fmt.Errorf("account: %w", err)
+	})
+	fillAccount(&res, fail)
+	return res, nil
+}
+
+func loadAccountZero(fail bool) (res account, err error) {
+	defer e.HandleZero(&res, &err)
+	fillAccount(&res, fail)
+	return res, nil
+}
+
+func loadAccountDefault(fail bool) (res account, err error) {
+	defer e.HandleWithDefault(&res, &err, account{ID: -1})
+	fillAccount(&res, fail)
+	return res, nil
+}
+
+func TestHandleResult(t *testing.T) {
+	t.Run("HandleResult: should replace both results", func(t *testing.T) {
+		res, err := loadAccountResult(true)
+		assert.Equal(t, account{Owner: "guest"}, res)
+		assert.EqualError(t, err, "account: load owner - error: closed")
+	})
+	t.Run("HandleZero: should reset the result", func(t *testing.T) {
+		res, err := loadAccountZero(true)
+		assert.Zero(t, res)
+		assert.EqualError(t, err, "load owner - error: closed")
+	})
+	t.Run("HandleWithDefault: should set the result to the default", func(t *testing.T) {
+		res, err := loadAccountDefault(true)
+		assert.Equal(t, account{ID: -1}, res)
+		assert.EqualError(t, err, "load owner - error: closed")
+	})
+	t.Run("should keep the results when nothing is thrown", func(t *testing.T) {
+		for _, load := range []func(bool) (account, error){loadAccountResult, loadAccountZero, loadAccountDefault} {
+			res, err := load(false)
+			assert.NoError(t, err)
+			assert.Equal(t, account{ID: 7, Owner: "ada"}, res)
+		}
+	})
+}
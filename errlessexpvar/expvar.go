@@ -0,0 +1,124 @@
+// Package errlessexpvar keeps the metrics of errless in memory and publishes them with expvar,
+// so they can be read from /debug/vars without an external metrics service.
+//
+//	restore := errless.Observe(errlessexpvar.Publish("errless"))
+//	defer restore()
+package errlessexpvar
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"sync"
+
+	"github.com/mfatihercik/errless"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the histogram buckets.
+var DefaultBuckets = []float64{0.0001, 0.001, 0.01, 0.1, 1, 10}
+
+// Metrics is an errless.Metrics that keeps its counters and histograms in memory.
+// It is an expvar.Var, and is safe for concurrent use.
+type Metrics struct {
+	mu         sync.Mutex
+	buckets    []float64
+	counters   map[string]map[string]int64
+	histograms map[string]map[string]*histogram
+}
+
+type histogram struct {
+	Count   int64            `json:"count"`
+	Sum     float64          `json:"sum"`
+	Buckets map[string]int64 `json:"buckets"`
+}
+
+// New returns empty metrics that are not published.
+func New() *Metrics {
+	return &Metrics{
+		buckets:    DefaultBuckets,
+		counters:   map[string]map[string]int64{},
+		histograms: map[string]map[string]*histogram{},
+	}
+}
+
+// Publish returns new metrics published with expvar under name.
+// Like expvar.Publish, it panics if name is already published.
+func Publish(name string) *Metrics {
+	m := New()
+	expvar.Publish(name, m)
+	return m
+}
+
+// IncCounter implements errless.Metrics.
+func (m *Metrics) IncCounter(name string, labels errless.MetricLabels) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	counter := m.counters[name]
+	if counter == nil {
+		counter = map[string]int64{}
+		m.counters[name] = counter
+	}
+	counter[key(labels)]++
+}
+
+// ObserveHistogram implements errless.Metrics.
+func (m *Metrics) ObserveHistogram(name string, value float64, labels errless.MetricLabels) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	series := m.histograms[name]
+	if series == nil {
+		series = map[string]*histogram{}
+		m.histograms[name] = series
+	}
+	h := series[key(labels)]
+	if h == nil {
+		h = &histogram{Buckets: map[string]int64{}}
+		series[key(labels)] = h
+	}
+	h.Count++
+	h.Sum += value
+	for _, bound := range m.buckets {
+		if value <= bound {
+			h.Buckets[fmt.Sprint(bound)]++
+		}
+	}
+}
+
+// Counter returns the value of the counter name for labels.
+func (m *Metrics) Counter(name string, labels errless.MetricLabels) int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counters[name][key(labels)]
+}
+
+// HistogramCount returns the number of values recorded in the histogram name for labels.
+func (m *Metrics) HistogramCount(name string, labels errless.MetricLabels) int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if h := m.histograms[name][key(labels)]; h != nil {
+		return h.Count
+	}
+	return 0
+}
+
+// String returns the metrics as JSON, which makes Metrics an expvar.Var.
+func (m *Metrics) String() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	all := make(map[string]any, len(m.counters)+len(m.histograms))
+	for name, counter := range m.counters {
+		all[name] = counter
+	}
+	for name, series := range m.histograms {
+		all[name] = series
+	}
+	out, err := json.Marshal(all)
+	if err != nil {
+		return "{}"
+	}
+	return string(out)
+}
+
+func key(labels errless.MetricLabels) string {
+	return fmt.Sprintf("site=%q,code=%q,outcome=%q", labels.Site, labels.Code, labels.Outcome)
+}
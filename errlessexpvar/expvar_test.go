@@ -0,0 +1,52 @@
+//go:build test
+
+package errlessexpvar_test
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"testing"
+
+	"github.com/mfatihercik/errless"
+	"github.com/mfatihercik/errless/errlessexpvar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	labels := errless.MetricLabels{Site: "app/load.go:12", Code: "io", Outcome: errless.OutcomeThrown}
+	m := errlessexpvar.New()
+	m.IncCounter(errless.MetricErrors, labels)
+	m.IncCounter(errless.MetricErrors, labels)
+	m.ObserveHistogram(errless.MetricUnwindSeconds, 0.005, labels)
+
+	assert.Equal(t, int64(2), m.Counter(errless.MetricErrors, labels))
+	assert.Equal(t, int64(1), m.HistogramCount(errless.MetricUnwindSeconds, labels))
+
+	var vars map[string]map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal([]byte(m.String()), &vars))
+	key := `site="app/load.go:12",code="io",outcome="thrown"`
+	assert.JSONEq(t, `2`, string(vars[errless.MetricErrors][key]))
+	assert.JSONEq(t, `{"count":1,"sum":0.005,"buckets":{"0.01":1,"0.1":1,"1":1,"10":1}}`,
+		string(vars[errless.MetricUnwindSeconds][key]))
+}
+
+// published is created once, since expvar names cannot be published again.
+var published = errlessexpvar.Publish("errless_test")
+
+func TestPublish(t *testing.T) {
+	m := published
+	t.Cleanup(errless.Observe(m))
+
+	load := func() (err error) {
+		defer errless.HandleErr(&err)
+		errless.Try(errors.New("failed")).Err()
+		return nil
+	}
+	require.Error(t, load())
+
+	assert.Same(t, m, expvar.Get("errless_test"))
+	assert.Contains(t, m.String(), `outcome=\"thrown\"`)
+	assert.Contains(t, m.String(), `outcome=\"escaped\"`)
+}
@@ -0,0 +1,37 @@
+//go:build test
+
+package errlesstest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mfatihercik/errless/errlesstest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := errlesstest.NewFakeClock(start)
+	after := clock.After(time.Minute)
+	now := clock.After(0)
+
+	assert.Equal(t, start, <-now)
+	clock.Advance(30 * time.Second)
+	assert.Empty(t, after)
+	clock.Advance(30 * time.Second)
+	assert.Equal(t, start.Add(time.Minute), <-after)
+	assert.Equal(t, start.Add(time.Minute), clock.Now())
+}
+
+func TestFakeClockBlockUntil(t *testing.T) {
+	clock := errlesstest.NewFakeClock(time.Time{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		<-clock.After(time.Second)
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	<-done
+}
@@ -0,0 +1,92 @@
+// Package errlesstest provides test helpers for code written with errless.
+package errlesstest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mfatihercik/errless"
+	"github.com/mfatihercik/errless/internal/throw"
+)
+
+// CaptureThrow runs fn and returns the error thrown by it, or nil if fn returned normally.
+// Panics that were not raised by errless are not recovered.
+func CaptureThrow(fn func()) error {
+	exp := capture(fn)
+	if exp == nil {
+		return nil
+	}
+	return exp.Err
+}
+
+// AssertThrows fails the test if fn does not throw.
+func AssertThrows(t testing.TB, fn func()) bool {
+	t.Helper()
+	if capture(fn) == nil {
+		t.Errorf("expected function to throw, but it returned normally")
+		return false
+	}
+	return true
+}
+
+// AssertThrowsIs fails the test if fn does not throw an error matching target with errors.Is.
+func AssertThrowsIs(t testing.TB, target error, fn func()) bool {
+	t.Helper()
+	exp := capture(fn)
+	if exp == nil {
+		t.Errorf("expected function to throw %q, but it returned normally", target)
+		return false
+	}
+	if !errors.Is(exp.Err, target) {
+		t.Errorf("expected thrown error to match %q\n\tthrown: %q\n\tat:     %s", target, exp.Err, exp.Site)
+		return false
+	}
+	return true
+}
+
+// AssertNoThrow fails the test if fn throws.
+func AssertNoThrow(t testing.TB, fn func()) bool {
+	t.Helper()
+	if exp := capture(fn); exp != nil {
+		t.Errorf("expected function not to throw\n\tthrown: %q\n\tat:     %s", exp.Err, exp.Site)
+		return false
+	}
+	return true
+}
+
+// RequireErrorChain stops the test unless err matches every target with errors.Is.
+func RequireErrorChain(t testing.TB, err error, targets ...error) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("expected an error matching %q, got nil", targets)
+		return
+	}
+	for _, target := range targets {
+		if !errors.Is(err, target) {
+			t.Fatalf("expected error chain to contain %q\n\terror: %q", target, err)
+			return
+		}
+	}
+}
+
+func capture(fn func()) (exp *throw.Exception) {
+	defer func() {
+		exp = throw.Recover(recover())
+	}()
+	fn()
+	return nil
+}
+
+// DefaultHandlers registers handlers with errless.SetDefaultHandlers until the test ends.
+// The handlers are process wide, so tests using it should not run in parallel.
+func DefaultHandlers(t testing.TB, handlers ...errless.HandlerFunc) {
+	t.Helper()
+	t.Cleanup(errless.SetDefaultHandlers(handlers...))
+}
+
+// PackageHandlers registers handlers for the package pkg until the test ends.
+// The handlers are process wide, so tests using it should not run in parallel.
+func PackageHandlers(t testing.TB, pkg string, handlers ...errless.HandlerFunc) {
+	t.Helper()
+	t.Cleanup(errless.Package(pkg).Handlers(handlers...))
+}
@@ -0,0 +1,93 @@
+//go:build test
+
+package errlesstest_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	e "github.com/mfatihercik/errless"
+	"github.com/mfatihercik/errless/errlesstest"
+	"github.com/stretchr/testify/assert"
+)
+
+var errNotFound = errors.New("not found")
+
+// recorder captures failures reported by the helpers under test.
+type recorder struct {
+	testing.TB
+	failures []string
+	fatal    bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+	r.fatal = true
+}
+
+func TestCaptureThrow(t *testing.T) {
+	t.Run("should return thrown error", func(t *testing.T) {
+		err := errlesstest.CaptureThrow(func() {
+			e.Try(errNotFound).ErrMessage("load")
+		})
+		assert.ErrorContains(t, err, "load - error: not found")
+	})
+
+	t.Run("should return nil when nothing is thrown", func(t *testing.T) {
+		assert.NoError(t, errlesstest.CaptureThrow(func() {}))
+	})
+
+	t.Run("shouldn't recover non exception panics", func(t *testing.T) {
+		assert.PanicsWithValue(t, "boom", func() {
+			_ = errlesstest.CaptureThrow(func() { panic("boom") })
+		})
+	})
+}
+
+func TestAssertions(t *testing.T) {
+	t.Run("AssertThrows", func(t *testing.T) {
+		r := &recorder{TB: t}
+		assert.True(t, errlesstest.AssertThrows(r, func() { e.Throw(errNotFound) }))
+		assert.False(t, errlesstest.AssertThrows(r, func() {}))
+		assert.Len(t, r.failures, 1)
+	})
+
+	t.Run("AssertThrowsIs should report throw site", func(t *testing.T) {
+		r := &recorder{TB: t}
+		assert.True(t, errlesstest.AssertThrowsIs(r, errNotFound, func() { e.Throw(errNotFound) }))
+		assert.False(t, errlesstest.AssertThrowsIs(r, errNotFound, func() {
+			e.Try1(0, errors.New("other")).Err()
+		}))
+		assert.Len(t, r.failures, 1)
+		assert.Contains(t, r.failures[0], "errlesstest_test.go:")
+	})
+
+	t.Run("AssertNoThrow should report throw site", func(t *testing.T) {
+		r := &recorder{TB: t}
+		assert.True(t, errlesstest.AssertNoThrow(r, func() { e.Throw(nil) }))
+		assert.False(t, errlesstest.AssertNoThrow(r, func() { e.Throw1(0, errNotFound) }))
+		assert.Len(t, r.failures, 1)
+		assert.Contains(t, r.failures[0], "errlesstest_test.go:")
+	})
+
+	t.Run("RequireErrorChain", func(t *testing.T) {
+		r := &recorder{TB: t}
+		wrapped := fmt.Errorf("outer: %w", errNotFound)
+		errlesstest.RequireErrorChain(r, wrapped, errNotFound)
+		assert.False(t, r.fatal)
+
+		errlesstest.RequireErrorChain(r, wrapped, errors.New("missing"))
+		assert.True(t, r.fatal)
+
+		r = &recorder{TB: t}
+		errlesstest.RequireErrorChain(r, nil, errNotFound)
+		assert.True(t, r.fatal)
+	})
+}
@@ -0,0 +1,31 @@
+package errlesstest
+
+import (
+	"testing"
+
+	"github.com/mfatihercik/errless/internal/fault"
+)
+
+// InjectAt makes the Try call at site behave as though the call returned err, until the test ends.
+// The site is written as "path/file.go:line" and matched against the end of the file name.
+// It requires the errless_fault build tag.
+func InjectAt(t testing.TB, site string, err error) {
+	t.Helper()
+	requireFaultTag(t)
+	t.Cleanup(fault.AddSite(site, err))
+}
+
+// InjectLabel makes the Try calls named with Label(label) behave as though the call returned err,
+// until the test ends. It requires the errless_fault build tag.
+func InjectLabel(t testing.TB, label string, err error) {
+	t.Helper()
+	requireFaultTag(t)
+	t.Cleanup(fault.AddLabel(label, err))
+}
+
+func requireFaultTag(t testing.TB) {
+	t.Helper()
+	if !fault.Enabled {
+		t.Fatalf("errlesstest: fault injection requires the errless_fault build tag")
+	}
+}
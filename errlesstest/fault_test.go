@@ -0,0 +1,57 @@
+//go:build test && errless_fault
+
+package errlesstest_test
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"testing"
+
+	e "github.com/mfatihercik/errless"
+	"github.com/mfatihercik/errless/errlesstest"
+	"github.com/stretchr/testify/assert"
+)
+
+var errInjected = errors.New("injected")
+
+func loadByLabel(id string) (n int, err error) {
+	defer e.HandleErr(&err)
+	return e.Try1(strconv.Atoi(id)).Label("db.load").ErrMessage("load"), nil
+}
+
+func TestInjectLabel(t *testing.T) {
+	t.Run("should inject error for labelled call", func(t *testing.T) {
+		errlesstest.InjectLabel(t, "db.load", errInjected)
+		_, err := loadByLabel("42")
+		assert.ErrorContains(t, err, "load - error: injected")
+	})
+
+	t.Run("should remove injection when test ends", func(t *testing.T) {
+		n, err := loadByLabel("42")
+		assert.NoError(t, err)
+		assert.Equal(t, 42, n)
+	})
+}
+
+func TestInjectAt(t *testing.T) {
+	_, _, line, _ := runtime.Caller(0)
+	load := func(id string) (n int, err error) {
+		defer e.HandleErr(&err)
+		return e.Try1(strconv.Atoi(id)).Err(), nil // line + 3
+	}
+
+	t.Run("should inject error for call site", func(t *testing.T) {
+		errlesstest.InjectAt(t, fmt.Sprintf("errlesstest/fault_test.go:%d", line+3), errInjected)
+		_, err := load("42")
+		assert.ErrorIs(t, err, errInjected)
+	})
+
+	t.Run("shouldn't inject error for other call sites", func(t *testing.T) {
+		errlesstest.InjectAt(t, fmt.Sprintf("errlesstest/fault_test.go:%d", line+2), errInjected)
+		n, err := load("42")
+		assert.NoError(t, err)
+		assert.Equal(t, 42, n)
+	})
+}
@@ -0,0 +1,63 @@
+package errlesstest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mfatihercik/errless/internal/throw"
+)
+
+// Guard fails the test when an error thrown by errless reaches the top of the test
+// without being handled, and reports the site of the Throw and the functions the
+// error unwound through instead of a panic dump. Stacks are captured from the call to
+// Guard until the function it returns runs, which must be deferred directly:
+//
+//	func TestImport(t *testing.T) {
+//		defer errlesstest.Guard(t)()
+//		runImport()
+//	}
+//
+// Panics that were not raised by errless are re-panicked.
+func Guard(t testing.TB) func() {
+	release := throw.Guard()
+	return func() {
+		defer release()
+		if exp := throw.Recover(recover()); exp != nil {
+			t.Errorf("%s", escaped(exp))
+		}
+	}
+}
+
+// Go runs fn on a new goroutine guarded like with Guard, and returns a channel that
+// is closed when fn returns. The test waits for fn before it ends.
+func Go(t testing.TB, fn func()) <-chan struct{} {
+	t.Helper()
+	done := make(chan struct{})
+	t.Cleanup(func() { <-done })
+	guard := Guard(t)
+	go func() {
+		defer close(done)
+		defer guard()
+		fn()
+	}()
+	return done
+}
+
+// escaped describes an exception that was not handled.
+func escaped(exp *throw.Exception) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "errless: error escaped without a Handle: %q\n\tthrown at: %s", exp.Err, exp.Site)
+	first := true
+	for _, site := range exp.Frames() {
+		if strings.HasPrefix(site.Function, "testing.") {
+			break
+		}
+		if first {
+			b.WriteString("\n\tunwound through:")
+			first = false
+		}
+		fmt.Fprintf(&b, "\n\t\t%s (%s)", site.Function, site.Short())
+	}
+	return b.String()
+}
@@ -0,0 +1,55 @@
+//go:build test
+
+package errlesstest_test
+
+import (
+	"testing"
+
+	e "github.com/mfatihercik/errless"
+	"github.com/mfatihercik/errless/errlesstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadRecord() {
+	e.Try(errNotFound).ErrMessage("load record")
+}
+
+func importRecords() {
+	loadRecord()
+}
+
+func guarded(r *recorder, fn func()) {
+	defer errlesstest.Guard(r)()
+	fn()
+}
+
+func TestGuard(t *testing.T) {
+	t.Run("should report the site and chain of an escaped error", func(t *testing.T) {
+		r := &recorder{}
+		guarded(r, importRecords)
+		require.Len(t, r.failures, 1)
+		failure := r.failures[0]
+		assert.Contains(t, failure, `error escaped without a Handle: "load record - error: not found"`)
+		assert.Contains(t, failure, "thrown at: ")
+		assert.Regexp(t, `guard_test.go:\d+\n\tunwound through:\n\t\t\S+\.loadRecord \(\w+/guard_test\.go:\d+\)\n\t\t\S+\.importRecords `, failure)
+		assert.NotContains(t, failure, "testing.")
+	})
+	t.Run("should not fail when nothing escapes", func(t *testing.T) {
+		r := &recorder{}
+		guarded(r, func() {})
+		assert.Empty(t, r.failures)
+	})
+	t.Run("shouldn't recover non exception panics", func(t *testing.T) {
+		assert.PanicsWithValue(t, "boom", func() {
+			guarded(&recorder{}, func() { panic("boom") })
+		})
+	})
+}
+
+func TestGo(t *testing.T) {
+	r := &recorder{TB: t}
+	<-errlesstest.Go(r, importRecords)
+	require.Len(t, r.failures, 1)
+	assert.Contains(t, r.failures[0], "load record - error: not found")
+}
@@ -0,0 +1,66 @@
+package errlesstest
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/mfatihercik/errless"
+)
+
+// Span is an in-memory errless.SpanRecorder. It is safe for concurrent use.
+type Span struct {
+	mu          sync.Mutex
+	errors      []error
+	status      errless.StatusCode
+	description string
+}
+
+// RecordError implements errless.SpanRecorder.
+func (s *Span) RecordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors = append(s.errors, err)
+}
+
+// SetStatus implements errless.SpanRecorder.
+func (s *Span) SetStatus(code errless.StatusCode, description string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status, s.description = code, description
+}
+
+// Errors returns the errors recorded on the span.
+func (s *Span) Errors() []error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]error(nil), s.errors...)
+}
+
+// Status returns the status of the span.
+func (s *Span) Status() (code errless.StatusCode, description string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status, s.description
+}
+
+type spanKey struct{}
+
+// spanTracer finds the Span stored in a context by StartSpan.
+type spanTracer struct{}
+
+func (spanTracer) SpanFromContext(ctx context.Context) errless.SpanRecorder {
+	if span, ok := ctx.Value(spanKey{}).(*Span); ok {
+		return span
+	}
+	return nil
+}
+
+// StartSpan returns a context carrying a new in-memory span, and installs a tracer that
+// finds it until the test ends.
+func StartSpan(t testing.TB, ctx context.Context) (context.Context, *Span) {
+	t.Helper()
+	t.Cleanup(errless.SetTracer(spanTracer{}))
+	span := &Span{}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
@@ -0,0 +1,13 @@
+//go:build !errless_fault
+
+package errless
+
+// injectAtSite is a no-op without the errless_fault build tag.
+func injectAtSite(err error) error {
+	return err
+}
+
+// injectAtLabel is a no-op without the errless_fault build tag.
+func injectAtLabel(_ string, err error) error {
+	return err
+}
@@ -0,0 +1,27 @@
+//go:build errless_fault
+
+package errless
+
+import (
+	"github.com/mfatihercik/errless/internal/fault"
+	"github.com/mfatihercik/errless/internal/throw"
+)
+
+// injectAtSite replaces err with the error injected for the caller's call site.
+func injectAtSite(err error) error {
+	if !fault.HasSites() {
+		return err
+	}
+	if injected := fault.AtSite(throw.Caller()); injected != nil {
+		return injected
+	}
+	return err
+}
+
+// injectAtLabel replaces err with the error injected for label.
+func injectAtLabel(label string, err error) error {
+	if injected := fault.AtLabel(label); injected != nil {
+		return injected
+	}
+	return err
+}
@@ -0,0 +1,43 @@
+package codemod
+
+import "go/ast"
+
+// NestedBlocks returns the statement lists directly nested in stmt.
+func NestedBlocks(stmt ast.Stmt) [][]ast.Stmt {
+	switch s := stmt.(type) {
+	case *ast.BlockStmt:
+		return [][]ast.Stmt{s.List}
+	case *ast.IfStmt:
+		blocks := [][]ast.Stmt{s.Body.List}
+		if s.Else != nil {
+			blocks = append(blocks, NestedBlocks(s.Else)...)
+		}
+		return blocks
+	case *ast.ForStmt:
+		return [][]ast.Stmt{s.Body.List}
+	case *ast.RangeStmt:
+		return [][]ast.Stmt{s.Body.List}
+	case *ast.SwitchStmt:
+		return clauses(s.Body)
+	case *ast.TypeSwitchStmt:
+		return clauses(s.Body)
+	case *ast.SelectStmt:
+		return clauses(s.Body)
+	case *ast.LabeledStmt:
+		return NestedBlocks(s.Stmt)
+	}
+	return nil
+}
+
+func clauses(body *ast.BlockStmt) [][]ast.Stmt {
+	blocks := make([][]ast.Stmt, 0, len(body.List))
+	for _, clause := range body.List {
+		switch c := clause.(type) {
+		case *ast.CaseClause:
+			blocks = append(blocks, c.Body)
+		case *ast.CommClause:
+			blocks = append(blocks, c.Body)
+		}
+	}
+	return blocks
+}
@@ -0,0 +1,238 @@
+// Package codemod holds the plumbing shared by the errless source rewriting tools:
+// loading and type checking packages, applying text edits and the command line driver.
+package codemod
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ImportPath is the import path of the errless package.
+const ImportPath = "github.com/mfatihercik/errless"
+
+// maxPasses bounds how often a rewriter is re-run on its own output to reach nested call sites.
+const maxPasses = 10
+
+// File is a parsed source file of a Package.
+type File struct {
+	Name string
+	Src  []byte
+	AST  *ast.File
+}
+
+// Package is a set of files type checked together.
+type Package struct {
+	Fset  *token.FileSet
+	Files []*File
+	Info  *types.Info
+}
+
+// Offset returns the byte offset of pos in its file.
+func (p *Package) Offset(pos token.Pos) int {
+	return p.Fset.File(pos).Offset(pos)
+}
+
+// Text returns the source text of node.
+func (p *Package) Text(f *File, node ast.Node) string {
+	return string(f.Src[p.Offset(node.Pos()):p.Offset(node.End())])
+}
+
+// Rewriter returns the edits for one file of a package.
+type Rewriter func(pkg *Package, f *File) []Edit
+
+// Loader parses and type checks packages. Type errors are ignored, so files with
+// unresolved imports are still rewritten where enough type information is available.
+type Loader struct {
+	fset     *token.FileSet
+	importer types.Importer
+}
+
+// NewLoader returns a Loader that imports dependencies from source.
+func NewLoader() *Loader {
+	fset := token.NewFileSet()
+	return &Loader{fset: fset, importer: importer.ForCompiler(fset, "source", nil)}
+}
+
+// Load parses the sources and type checks them as a single package.
+func (l *Loader) Load(names []string, srcs [][]byte) (*Package, error) {
+	pkg := &Package{
+		Fset: l.fset,
+		Info: &types.Info{
+			Types:  map[ast.Expr]types.TypeAndValue{},
+			Defs:   map[*ast.Ident]types.Object{},
+			Uses:   map[*ast.Ident]types.Object{},
+			Scopes: map[ast.Node]*types.Scope{},
+		},
+	}
+	files := make([]*ast.File, 0, len(names))
+	for i, name := range names {
+		f, err := parser.ParseFile(l.fset, name, srcs[i], parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		pkg.Files = append(pkg.Files, &File{Name: name, Src: srcs[i], AST: f})
+		files = append(files, f)
+	}
+	conf := types.Config{Importer: l.importer, Error: func(error) {}}
+	_, _ = conf.Check(files[0].Name.Name, l.fset, files, pkg.Info)
+	return pkg, nil
+}
+
+// Rewrite applies rewrite to the sources until it reports no more edits and returns the formatted results.
+func (l *Loader) Rewrite(names []string, srcs [][]byte, rewrite Rewriter) ([][]byte, error) {
+	out := append([][]byte(nil), srcs...)
+	for pass := 0; pass < maxPasses; pass++ {
+		pkg, err := l.Load(names, out)
+		if err != nil {
+			return nil, err
+		}
+		changed := false
+		for i, f := range pkg.Files {
+			edits := rewrite(pkg, f)
+			if len(edits) == 0 {
+				continue
+			}
+			src, err := format.Source(Apply(f.Src, edits))
+			if err != nil {
+				return nil, fmt.Errorf("%s: formatting rewritten source: %w", f.Name, err)
+			}
+			changed = changed || !bytes.Equal(src, out[i])
+			out[i] = src
+		}
+		if !changed {
+			break
+		}
+	}
+	return out, nil
+}
+
+// Main runs a rewriting tool with the standard -w and -diff flags and returns the exit code.
+func Main(name string, rewrite Rewriter) int {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	write := flags.Bool("w", false, "write result to (source) file instead of stdout")
+	diff := flags.Bool("diff", false, "display diffs instead of rewriting files")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: %s [-w | -diff] [path ...]\n", name)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(os.Args[1:])
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	packages, err := collect(flags.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		return 1
+	}
+	loader := NewLoader()
+	exit := 0
+	for _, names := range packages {
+		if err := run(loader, names, rewrite, *write, *diff, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			exit = 1
+		}
+	}
+	return exit
+}
+
+func run(loader *Loader, names []string, rewrite Rewriter, write, diff bool, stdout io.Writer) error {
+	srcs := make([][]byte, len(names))
+	for i, name := range names {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		srcs[i] = src
+	}
+	out, err := loader.Rewrite(names, srcs, rewrite)
+	if err != nil {
+		return err
+	}
+	for i, name := range names {
+		switch {
+		case diff:
+			_, err = stdout.Write(Diff(name+".orig", name, srcs[i], out[i]))
+		case write:
+			if !bytes.Equal(srcs[i], out[i]) {
+				err = os.WriteFile(name, out[i], 0o600)
+			}
+		default:
+			_, err = stdout.Write(out[i])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collect groups the Go files named by paths by directory and package name.
+// A path ending in "/..." includes all directories below it.
+func collect(paths []string) ([][]string, error) {
+	groups := map[string][]string{}
+	add := func(name string) error {
+		f, err := parser.ParseFile(token.NewFileSet(), name, nil, parser.PackageClauseOnly)
+		if err != nil {
+			return err
+		}
+		key := filepath.Dir(name) + "\x00" + f.Name.Name
+		groups[key] = append(groups[key], name)
+		return nil
+	}
+	for _, path := range paths {
+		recursive := strings.HasSuffix(path, "/...")
+		path = strings.TrimSuffix(path, "/...")
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			if err := add(path); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		err = filepath.WalkDir(path, func(name string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if name != path && (!recursive || d.Name() == "testdata" || strings.HasPrefix(d.Name(), ".")) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(name, ".go") {
+				return add(name)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	packages := make([][]string, 0, len(keys))
+	for _, key := range keys {
+		packages = append(packages, groups[key])
+	}
+	return packages, nil
+}
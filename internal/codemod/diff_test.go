@@ -0,0 +1,32 @@
+//go:build test
+
+package codemod_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mfatihercik/errless/internal/codemod"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	t.Run("should return nil for equal input", func(t *testing.T) {
+		assert.Nil(t, codemod.Diff("a", "b", []byte("x\n"), []byte("x\n")))
+	})
+
+	t.Run("should print hunks with context", func(t *testing.T) {
+		old := strings.Repeat("same\n", 10) + "old\n" + strings.Repeat("same\n", 10)
+		updated := strings.Repeat("same\n", 10) + "new\nadded\n" + strings.Repeat("same\n", 10)
+		want := "--- a\n+++ b\n@@ -8,7 +8,8 @@\n same\n same\n same\n-old\n+new\n+added\n same\n same\n same\n"
+		assert.Equal(t, want, string(codemod.Diff("a", "b", []byte(old), []byte(updated))))
+	})
+
+	t.Run("should split distant changes into hunks", func(t *testing.T) {
+		old := "a\n" + strings.Repeat("same\n", 10) + "b\n"
+		updated := "A\n" + strings.Repeat("same\n", 10) + "B\n"
+		diff := string(codemod.Diff("a", "b", []byte(old), []byte(updated)))
+		assert.Contains(t, diff, "@@ -1,4 +1,4 @@\n-a\n+A\n")
+		assert.Contains(t, diff, "@@ -9,4 +9,4 @@\n same\n same\n same\n-b\n+B\n")
+	})
+}
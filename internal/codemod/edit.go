@@ -0,0 +1,134 @@
+package codemod
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Edit replaces the source bytes in [Start, End) with Text.
+type Edit struct {
+	Start, End int
+	Text       string
+}
+
+// Overlaps reports whether e and other touch the same source bytes.
+func (e Edit) Overlaps(other Edit) bool {
+	if e.Start == e.End || other.Start == other.End {
+		// insertions only conflict with edits that replace text around them
+		return (e.Start > other.Start && e.Start < other.End) || (other.Start > e.Start && other.Start < e.End)
+	}
+	return e.Start < other.End && other.Start < e.End
+}
+
+// Apply applies non-overlapping edits to src.
+func Apply(src []byte, edits []Edit) []byte {
+	sorted := append([]Edit(nil), edits...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+	out := make([]byte, 0, len(src))
+	last := 0
+	for _, e := range sorted {
+		out = append(out, src[last:e.Start]...)
+		out = append(out, e.Text...)
+		last = e.End
+	}
+	return append(out, src[last:]...)
+}
+
+// Accept appends group to edits unless one of its edits overlaps an edit already accepted.
+func Accept(edits, group []Edit) ([]Edit, bool) {
+	for _, g := range group {
+		for _, e := range edits {
+			if g.Overlaps(e) {
+				return edits, false
+			}
+		}
+	}
+	return append(edits, group...), true
+}
+
+// ImportName returns the name the file uses for the errless package, or "" if it is not imported.
+func ImportName(f *ast.File) string {
+	if spec := ImportSpec(f, ImportPath); spec != nil {
+		if spec.Name != nil {
+			return spec.Name.Name
+		}
+		return "errless"
+	}
+	return ""
+}
+
+// ImportSpec returns the import of path in f, or nil.
+func ImportSpec(f *ast.File, path string) *ast.ImportSpec {
+	for _, spec := range f.Imports {
+		if p, _ := strconv.Unquote(spec.Path.Value); p == path {
+			return spec
+		}
+	}
+	return nil
+}
+
+// AddImport returns the edit that adds the import of path to f.
+func (p *Package) AddImport(f *File, path string) Edit {
+	quoted := strconv.Quote(path)
+	for _, decl := range f.AST.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		if gen.Rparen.IsValid() {
+			// standard library imports join the existing standard library group
+			if last := lastStd(gen); last != nil && isStd(path) {
+				at := p.Offset(last.End())
+				return Edit{Start: at, End: at, Text: "\n" + quoted}
+			}
+			at := p.Offset(gen.Rparen)
+			return Edit{Start: at, End: at, Text: "\n" + quoted + "\n"}
+		}
+		at := p.Offset(gen.End())
+		return Edit{Start: at, End: at, Text: "\nimport " + quoted}
+	}
+	at := p.Offset(f.AST.Name.End())
+	return Edit{Start: at, End: at, Text: "\n\nimport " + quoted}
+}
+
+// DeleteLines returns the edit that removes node together with the lines it occupies.
+func (p *Package) DeleteLines(f *File, node ast.Node) Edit {
+	start, end := p.Offset(node.Pos()), p.Offset(node.End())
+	for start > 0 && (f.Src[start-1] == ' ' || f.Src[start-1] == '\t') {
+		start--
+	}
+	if end < len(f.Src) && f.Src[end] == '\n' {
+		end++
+	}
+	return Edit{Start: start, End: end}
+}
+
+// RemoveImport returns the edit that removes spec from f.
+func (p *Package) RemoveImport(f *File, spec *ast.ImportSpec) Edit {
+	for _, decl := range f.AST.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if ok && gen.Tok == token.IMPORT && len(gen.Specs) == 1 && gen.Specs[0] == spec {
+			return p.DeleteLines(f, gen)
+		}
+	}
+	return p.DeleteLines(f, spec)
+}
+
+func lastStd(gen *ast.GenDecl) *ast.ImportSpec {
+	var last *ast.ImportSpec
+	for _, spec := range gen.Specs {
+		imp := spec.(*ast.ImportSpec)
+		if path, _ := strconv.Unquote(imp.Path.Value); isStd(path) {
+			last = imp
+		}
+	}
+	return last
+}
+
+func isStd(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
@@ -0,0 +1,6 @@
+//go:build errless_fault
+
+package fault
+
+// Enabled reports whether errless was built with the errless_fault tag.
+const Enabled = true
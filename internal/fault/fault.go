@@ -0,0 +1,76 @@
+// Package fault holds the errors registered for fault injection. The registry is
+// only consulted by errless when it is built with the errless_fault tag.
+package fault
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mfatihercik/errless/internal/throw"
+)
+
+type registry struct {
+	mu     sync.RWMutex
+	sites  map[string]error
+	labels map[string]error
+}
+
+var faults = registry{sites: map[string]error{}, labels: map[string]error{}}
+
+// AddSite registers err for the call site "path/file.go:line". The path is matched
+// against the end of the absolute file name.
+func AddSite(site string, err error) (remove func()) {
+	return faults.add(faults.sites, site, err)
+}
+
+// AddLabel registers err for the call sites with the given label.
+func AddLabel(label string, err error) (remove func()) {
+	return faults.add(faults.labels, label, err)
+}
+
+// AtSite returns the error registered for site, or nil.
+func AtSite(site throw.Site) error {
+	faults.mu.RLock()
+	defer faults.mu.RUnlock()
+	if len(faults.sites) == 0 {
+		return nil
+	}
+	location := site.File + ":" + strconv.Itoa(site.Line)
+	for key, err := range faults.sites {
+		if location == key || strings.HasSuffix(location, "/"+key) {
+			return err
+		}
+	}
+	return nil
+}
+
+// AtLabel returns the error registered for label, or nil.
+func AtLabel(label string) error {
+	faults.mu.RLock()
+	defer faults.mu.RUnlock()
+	return faults.labels[label]
+}
+
+// HasSites reports whether any call site is registered, so callers can avoid walking the stack.
+func HasSites() bool {
+	faults.mu.RLock()
+	defer faults.mu.RUnlock()
+	return len(faults.sites) > 0
+}
+
+func (r *registry) add(m map[string]error, key string, err error) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	previous, existed := m[key]
+	m[key] = err
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if existed {
+			m[key] = previous
+		} else {
+			delete(m, key)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Package throw holds the panic value errless uses to unwind a function, so it
+// can be shared between errless and its companion packages.
+package throw
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const modulePath = "github.com/mfatihercik/errless"
+
+// Exception is the value passed to panic by errless.Throw.
+type Exception struct {
+	Err  error
+	Site Site
+	// Time is when the error was thrown. It is only set while metrics are observed.
+	Time time.Time
+	// Stack holds the program counters of the goroutine's stack when the error was thrown.
+	// It is only set while stacks are captured, see Stack.
+	Stack []uintptr
+}
+
+// Frames returns the functions the exception unwinds through, starting at its site.
+func (e Exception) Frames() []Site {
+	var sites []Site
+	frames := runtime.CallersFrames(e.Stack)
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" && !isInternal(frame.Function) {
+			sites = append(sites, Site{Function: frame.Function, File: frame.File, Line: frame.Line})
+		}
+		if !more {
+			return sites
+		}
+	}
+}
+
+// Site is the source location of the user code that caused a throw.
+type Site struct {
+	Function string
+	File     string
+	Line     int
+}
+
+func (s Site) String() string {
+	if s.File == "" {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s:%d", s.File, s.Line)
+}
+
+// Short returns the position as "dir/file:line", which stays the same across machines.
+func (s Site) Short() string {
+	if s.File == "" {
+		return "unknown"
+	}
+	file := s.File
+	if i := strings.LastIndex(file, "/"); i >= 0 {
+		if j := strings.LastIndex(file[:i], "/"); j >= 0 {
+			file = file[j+1:]
+		}
+	}
+	return fmt.Sprintf("%s:%d", file, s.Line)
+}
+
+// Recover converts a recovered value into an Exception.
+// Panics that were not raised by errless are re-panicked with the original value.
+func Recover(r any) *Exception {
+	if r == nil {
+		return nil
+	}
+	if e, ok := r.(Exception); ok {
+		return &e
+	}
+	panic(r)
+}
+
+// Caller returns the first frame on the stack that is not part of errless itself.
+func Caller() Site {
+	var pcs [32]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !isInternal(frame.Function) {
+			return Site{Function: frame.Function, File: frame.File, Line: frame.Line}
+		}
+		if !more {
+			return Site{}
+		}
+	}
+}
+
+// guards is the number of running test guards.
+var guards atomic.Int32
+
+// Guard captures stacks until release is called. Guards may overlap, as parallel tests do.
+func Guard() (release func()) {
+	guards.Add(1)
+	var once sync.Once
+	return func() {
+		once.Do(func() { guards.Add(-1) })
+	}
+}
+
+// Stack returns the program counters of the caller's stack, or nil unless force is set or
+// a guard runs, as walking the stack is costly on the error path.
+func Stack(force bool) []uintptr {
+	if !force && guards.Load() == 0 {
+		return nil
+	}
+	pcs := make([]uintptr, 64)
+	return pcs[:runtime.Callers(2, pcs)]
+}
+
+// isInternal reports whether function belongs to errless, or to the runtime
+// frames that run deferred errless calls while panicking.
+func isInternal(function string) bool {
+	return strings.HasPrefix(function, modulePath+".") ||
+		strings.HasPrefix(function, modulePath+"/internal/") ||
+		strings.HasPrefix(function, "runtime.")
+}
+
+// Package returns the import path of the package of the site's function.
+func (s Site) Package() string {
+	slash := strings.LastIndex(s.Function, "/")
+	dot := strings.Index(s.Function[slash+1:], ".")
+	if dot < 0 {
+		return s.Function
+	}
+	return s.Function[:slash+1+dot]
+}
@@ -0,0 +1,30 @@
+//go:build test
+
+package throw_test
+
+import (
+	"testing"
+
+	"github.com/mfatihercik/errless/internal/throw"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStack(t *testing.T) {
+	t.Run("should not capture stacks without a guard", func(t *testing.T) {
+		assert.Nil(t, throw.Stack(false))
+		assert.NotNil(t, throw.Stack(true))
+	})
+	t.Run("should capture stacks while a guard runs", func(t *testing.T) {
+		release := throw.Guard()
+		outer := throw.Guard()
+		outer()
+		outer()
+		frames := throw.Exception{Stack: throw.Stack(false)}.Frames()
+		release()
+		// The frames of this test belong to errless, so the first one left is the test runner.
+		if assert.NotEmpty(t, frames) {
+			assert.Equal(t, "testing.tRunner", frames[0].Function)
+		}
+		assert.Nil(t, throw.Stack(false))
+	})
+}
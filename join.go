@@ -0,0 +1,91 @@
+package errless
+
+import "errors"
+
+// Flatten returns the leaves of the tree of errors joined with Unwrap() []error, such as the
+// errors built by errors.Join. Wrappers that hold a joined error are followed down to it, so
+// their text is not part of the leaves. An error that does not hold a joined error is its own
+// only leaf, and a nil error has none.
+func Flatten(err error) []error {
+	return flatten(err, nil)
+}
+
+func flatten(err error, leaves []error) []error {
+	if err == nil {
+		return leaves
+	}
+	for e := err; e != nil; {
+		switch u := e.(type) {
+		case interface{ Unwrap() []error }:
+			for _, member := range u.Unwrap() {
+				leaves = flatten(member, leaves)
+			}
+			return leaves
+		case interface{ Unwrap() error }:
+			e = u.Unwrap()
+		default:
+			e = nil
+		}
+	}
+	return append(leaves, err)
+}
+
+// AnyLeaf returns a filter that matches errors with a leaf matching filter, see Flatten.
+func AnyLeaf(filter IfFunc) IfFunc {
+	return func(err error) bool {
+		for _, leaf := range Flatten(err) {
+			if filter(leaf) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// AllLeaves returns a filter that matches errors whose leaves all match filter, see Flatten.
+func AllLeaves(filter IfFunc) IfFunc {
+	return func(err error) bool {
+		leaves := Flatten(err)
+		for _, leaf := range leaves {
+			if !filter(leaf) {
+				return false
+			}
+		}
+		return len(leaves) > 0
+	}
+}
+
+// MapLeaves returns a handler that applies handle to each leaf of the error, see Flatten,
+// and joins the errors it returns. Leaves handled to nil are dropped, and the handler
+// returns nil if no leaf is left.
+func MapLeaves(handle HandlerFunc) HandlerFunc {
+	return func(err error) error {
+		leaves := Flatten(err)
+		handled := leaves[:0:0]
+		for _, leaf := range leaves {
+			if leaf = handle(leaf); leaf != nil {
+				handled = append(handled, leaf)
+			}
+		}
+		return join(handled)
+	}
+}
+
+// FilterLeaves returns a handler that keeps the leaves of the error matching filter, see
+// Flatten, and joins them. It returns nil if no leaf matches, so Throw does not panic.
+func FilterLeaves(filter IfFunc) HandlerFunc {
+	return MapLeaves(func(err error) error {
+		if filter(err) {
+			return err
+		}
+		return nil
+	})
+}
+
+// join returns the single error of errs, or errs joined with errors.Join.
+func join(errs []error) error {
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
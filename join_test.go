@@ -0,0 +1,82 @@
+//go:build test
+
+package errless_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	e "github.com/mfatihercik/errless"
+	"github.com/mfatihercik/errless/errlesstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	errLeafA = errors.New("leaf a")
+	errLeafB = errors.New("leaf b")
+	errLeafC = errors.New("leaf c")
+)
+
+func TestFlatten(t *testing.T) {
+	tree := multiError{errLeafA, fmt.Errorf("nested: %w", multiError{errLeafB, errLeafC})}
+	assert.Equal(t, []error{errLeafA, errLeafB, errLeafC}, e.Flatten(tree))
+	assert.Equal(t, []error{errLeafA, errLeafB, errLeafC}, e.Flatten(fmt.Errorf("top: %w", tree)))
+
+	wrapped := fmt.Errorf("load: %w", errLeafA)
+	assert.Equal(t, []error{wrapped}, e.Flatten(wrapped))
+	assert.Empty(t, e.Flatten(nil))
+}
+
+func TestLeafPredicates(t *testing.T) {
+	tree := multiError{errLeafA, codedError{"io"}}
+	t.Run("AnyLeaf", func(t *testing.T) {
+		assert.True(t, e.AnyLeaf(e.Is(errLeafA))(tree))
+		assert.True(t, e.AnyLeaf(e.IfCode("io"))(tree))
+		assert.False(t, e.AnyLeaf(e.Contains("leaf b"))(tree))
+	})
+	t.Run("AllLeaves", func(t *testing.T) {
+		assert.True(t, e.AllLeaves(e.IsNot(errLeafB))(tree))
+		assert.False(t, e.AllLeaves(e.Is(errLeafA))(tree))
+		assert.False(t, e.AllLeaves(e.Is(errLeafA))(nil))
+	})
+	t.Run("should filter with If", func(t *testing.T) {
+		errlesstest.AssertNoThrow(t, func() {
+			e.Try(tree).If(e.AllLeaves(e.Is(errLeafA))).Err()
+		})
+		errlesstest.AssertThrowsIs(t, errLeafA, func() {
+			e.Try(tree).If(e.AnyLeaf(e.Is(errLeafA))).Err()
+		})
+	})
+}
+
+func TestLeafHandlers(t *testing.T) {
+	tree := multiError{errLeafA, errLeafB, errLeafC}
+	t.Run("MapLeaves", func(t *testing.T) {
+		err := e.MapLeaves(func(err error) error {
+			if errors.Is(err, errLeafB) {
+				return nil
+			}
+			return fmt.Errorf("handled: %w", err)
+		})(tree)
+		assert.EqualError(t, err, "handled: leaf a\nhandled: leaf c")
+		assert.Len(t, e.Flatten(err), 2)
+		assert.Nil(t, e.MapLeaves(func(error) error { return nil })(tree))
+	})
+	t.Run("FilterLeaves", func(t *testing.T) {
+		err := e.FilterLeaves(e.IsNot(errLeafA))(tree)
+		assert.Equal(t, []error{errLeafB, errLeafC}, e.Flatten(err))
+		assert.Equal(t, errLeafC, e.FilterLeaves(e.Is(errLeafC))(tree))
+	})
+	t.Run("should not throw when every leaf is filtered out", func(t *testing.T) {
+		errlesstest.AssertNoThrow(t, func() {
+			e.Try(tree).Err(e.FilterLeaves(e.Contains("leaf d")))
+		})
+		err := errlesstest.CaptureThrow(func() {
+			e.Try(tree).Err(e.FilterLeaves(e.Contains("leaf b")))
+		})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errLeafB)
+	})
+}
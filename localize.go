@@ -0,0 +1,129 @@
+package errless
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"sync/atomic"
+)
+
+// LocalizedError is an error whose message is looked up by key in a Catalog.
+type LocalizedError struct {
+	Err  error
+	Key  string
+	Args []any
+}
+
+func (e *LocalizedError) Error() string {
+	if e.Err == nil {
+		return e.Key
+	}
+	return e.Key + " - error: " + e.Err.Error()
+}
+
+func (e *LocalizedError) Unwrap() error { return e.Err }
+
+// Localized returns a handler that wraps the error in a LocalizedError with key and args.
+func Localized(key string, args ...any) HandlerFunc {
+	return func(err error) error {
+		return &LocalizedError{Err: err, Key: key, Args: args}
+	}
+}
+
+// Catalog holds the messages of the localized errors.
+type Catalog interface {
+	// Message returns the fmt format of key in lang.
+	Message(lang, key string) (format string, ok bool)
+}
+
+type catalogHolder struct {
+	catalog Catalog
+}
+
+var catalog atomic.Pointer[catalogHolder]
+
+// SetCatalog sets the catalog used by Translate for the whole process.
+// It returns a function that restores the previous catalog.
+func SetCatalog(c Catalog) (restore func()) {
+	previous := catalog.Swap(&catalogHolder{catalog: c})
+	return func() {
+		catalog.Store(previous)
+	}
+}
+
+// Translate renders the localized errors in the chain of err in lang, outermost first and
+// separated by ": ". A key missing from the catalog is rendered as the key itself.
+// If the chain has no localized error, Translate returns err.Error().
+func Translate(err error, lang string) string {
+	if err == nil {
+		return ""
+	}
+	var c Catalog
+	if h := catalog.Load(); h != nil {
+		c = h.catalog
+	}
+	var messages []string
+	walk(err, func(err error) bool {
+		if l, ok := err.(*LocalizedError); ok {
+			messages = append(messages, translate(c, lang, l))
+		}
+		return false
+	})
+	if len(messages) == 0 {
+		return err.Error()
+	}
+	return strings.Join(messages, ": ")
+}
+
+func translate(c Catalog, lang string, l *LocalizedError) string {
+	if c != nil {
+		if format, ok := c.Message(lang, l.Key); ok {
+			return fmt.Sprintf(format, l.Args...)
+		}
+	}
+	return l.Key
+}
+
+// MapCatalog is a Catalog of the messages of each language, keyed by language and then by key.
+// A language such as "pt-BR" falls back to "pt" for the keys it does not have.
+type MapCatalog map[string]map[string]string
+
+// Message implements Catalog.
+func (c MapCatalog) Message(lang, key string) (string, bool) {
+	for {
+		if format, ok := c[lang][key]; ok {
+			return format, true
+		}
+		i := strings.LastIndexAny(lang, "-_")
+		if i < 0 {
+			return "", false
+		}
+		lang = lang[:i]
+	}
+}
+
+// LoadCatalog reads a MapCatalog from the JSON files of fsys matching pattern, such as
+// "locales/*.json". Each file holds an object of keys and messages, and is named after its
+// language, e.g. "locales/en.json". It works with embed.FS and os.DirFS.
+func LoadCatalog(fsys fs.FS, pattern string) (MapCatalog, error) {
+	files, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+	c := MapCatalog{}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		messages := map[string]string{}
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("errless: catalog %s: %w", file, err)
+		}
+		lang := strings.TrimSuffix(path.Base(file), path.Ext(file))
+		c[lang] = messages
+	}
+	return c, nil
+}
@@ -0,0 +1,77 @@
+//go:build test
+
+package errless_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	e "github.com/mfatihercik/errless"
+	"github.com/mfatihercik/errless/errlesstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadCatalog(t *testing.T) e.MapCatalog {
+	c, err := e.LoadCatalog(os.DirFS("testdata"), "locales/*.json")
+	require.NoError(t, err)
+	t.Cleanup(e.SetCatalog(c))
+	return c
+}
+
+func TestLocalized(t *testing.T) {
+	t.Run("should keep the key, args and cause", func(t *testing.T) {
+		err := errlesstest.CaptureThrow(func() {
+			e.Try(errDB).Err(e.Localized("account.not_found", "42"))
+		})
+		var localized *e.LocalizedError
+		require.ErrorAs(t, err, &localized)
+		assert.Equal(t, "account.not_found", localized.Key)
+		assert.Equal(t, []any{"42"}, localized.Args)
+		assert.ErrorIs(t, err, errDB)
+		assert.EqualError(t, err, "account.not_found - error: "+errDB.Error())
+	})
+}
+
+func TestTranslate(t *testing.T) {
+	err := e.Localized("request.failed")(fmt.Errorf("load: %w", e.Localized("account.not_found", "42")(errDB)))
+	t.Run("should render the chain in the language", func(t *testing.T) {
+		loadCatalog(t)
+		assert.Equal(t, "the request failed: account 42 was not found", e.Translate(err, "en"))
+		assert.Equal(t, "istek başarısız oldu: 42 hesabı bulunamadı", e.Translate(err, "tr"))
+	})
+	t.Run("should fall back to the base language", func(t *testing.T) {
+		loadCatalog(t)
+		assert.Equal(t, "a solicitação falhou: a conta 42 não foi encontrada", e.Translate(err, "pt-BR"))
+	})
+	t.Run("should render missing keys as the key", func(t *testing.T) {
+		loadCatalog(t)
+		assert.Equal(t, "request.failed: account.not_found", e.Translate(err, "de"))
+	})
+	t.Run("should render errors without keys", func(t *testing.T) {
+		loadCatalog(t)
+		assert.Equal(t, errDB.Error(), e.Translate(errDB, "en"))
+		assert.Equal(t, "", e.Translate(nil, "en"))
+	})
+	t.Run("should render keys without a catalog", func(t *testing.T) {
+		t.Cleanup(e.SetCatalog(nil))
+		assert.Equal(t, "request.failed: account.not_found", e.Translate(err, "en"))
+	})
+}
+
+func TestLoadCatalog(t *testing.T) {
+	t.Run("should name languages after the files", func(t *testing.T) {
+		c := loadCatalog(t)
+		assert.Len(t, c, 4)
+		format, ok := c.Message("tr", "request.failed")
+		assert.True(t, ok)
+		assert.Equal(t, "istek başarısız oldu", format)
+	})
+	t.Run("should report invalid files", func(t *testing.T) {
+		fsys := fstest.MapFS{"locales/en.json": {Data: []byte("[")}}
+		_, err := e.LoadCatalog(fsys, "locales/*.json")
+		assert.ErrorContains(t, err, "locales/en.json")
+	})
+}
@@ -0,0 +1,131 @@
+package errless
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/mfatihercik/errless/internal/throw"
+)
+
+// Names of the metrics errless reports.
+const (
+	// MetricErrors counts the errors errless handled, by site, code and outcome.
+	MetricErrors = "errless_errors_total"
+	// MetricUnwindSeconds is the time between throwing an error and recovering it.
+	MetricUnwindSeconds = "errless_unwind_seconds"
+)
+
+// Outcomes of a handled error.
+const (
+	// OutcomeThrown is an error Throw panicked with.
+	OutcomeThrown = "thrown"
+	// OutcomeHandled is an error a handler of Throw replaced with nil.
+	OutcomeHandled = "handled"
+	// OutcomeFallback is an error replaced by the value of a Fallback or Or.
+	OutcomeFallback = "fallback"
+	// OutcomeEscaped is an error a Handle, HandleErr or HandleCtx returned from its function.
+	OutcomeEscaped = "escaped"
+	// OutcomeRecovered is an error a Handle or Catch recovered without returning it.
+	OutcomeRecovered = "recovered"
+)
+
+// MetricLabels are the labels of a measurement.
+type MetricLabels struct {
+	// Site is the position of the check, as "dir/file.go:line".
+	Site string
+	// Code is the code of the error, see ErrorCode.
+	Code string
+	// Outcome is one of the Outcome constants.
+	Outcome string
+}
+
+// Metrics receives the measurements of the errors handled by errless.
+// Its methods are called from the goroutines that throw, so they must be safe for concurrent use.
+type Metrics interface {
+	// IncCounter adds one to the counter name.
+	IncCounter(name string, labels MetricLabels)
+	// ObserveHistogram records value in the histogram name.
+	ObserveHistogram(name string, value float64, labels MetricLabels)
+}
+
+type metricsHolder struct {
+	metrics Metrics
+}
+
+var observed atomic.Pointer[metricsHolder]
+
+// Observe sets the metrics errless reports to for the whole process. A nil m stops reporting.
+// It returns a function that restores the previous metrics.
+func Observe(m Metrics) (restore func()) {
+	var next *metricsHolder
+	if m != nil {
+		next = &metricsHolder{metrics: m}
+	}
+	previous := observed.Swap(next)
+	return func() {
+		observed.Store(previous)
+	}
+}
+
+// ErrorCode returns the first code in the chain of err that is returned by a Code() string
+// method and is not empty, or an empty string.
+func ErrorCode(err error) string {
+	var code string
+	walk(err, func(err error) bool {
+		if c, ok := err.(interface{ Code() string }); ok {
+			code = c.Code()
+		}
+		return code != ""
+	})
+	return code
+}
+
+func currentMetrics() Metrics {
+	if h := observed.Load(); h != nil {
+		return h.metrics
+	}
+	return nil
+}
+
+func observeError(err error, site throw.Site, outcome string) {
+	if m := currentMetrics(); m != nil {
+		m.IncCounter(MetricErrors, MetricLabels{Site: site.Short(), Code: ErrorCode(err), Outcome: outcome})
+	}
+}
+
+func observeHandled(err error) {
+	if observed.Load() != nil {
+		observeError(err, throw.Caller(), OutcomeHandled)
+	}
+}
+
+func observeFallback(err error) {
+	if err != nil && observed.Load() != nil {
+		observeError(err, throw.Caller(), OutcomeFallback)
+	}
+}
+
+// observeRecover reports the outcome of a recovered exception. err is the error after the
+// handler of the recover, so a nil err means the exception was swallowed.
+func observeRecover(exp *throw.Exception, err error) {
+	m := currentMetrics()
+	if m == nil {
+		return
+	}
+	labels := MetricLabels{Site: exp.Site.Short(), Code: ErrorCode(exp.Err), Outcome: OutcomeEscaped}
+	if err == nil {
+		labels.Outcome = OutcomeRecovered
+	}
+	m.IncCounter(MetricErrors, labels)
+	if !exp.Time.IsZero() {
+		m.ObserveHistogram(MetricUnwindSeconds, clock().Now().Sub(exp.Time).Seconds(), labels)
+	}
+}
+
+// thrownAt returns the time stamp of a throw, or the zero time if metrics are not observed.
+func thrownAt() time.Time {
+	if observed.Load() == nil {
+		return time.Time{}
+	}
+	return clock().Now()
+}
@@ -0,0 +1,101 @@
+//go:build test
+
+package errless_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	e "github.com/mfatihercik/errless"
+	"github.com/mfatihercik/errless/errlesstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type codedError struct{ code string }
+
+func (c codedError) Error() string { return "coded " + c.code }
+func (c codedError) Code() string  { return c.code }
+
+// metricEvent is a counter increment seen by eventMetrics.
+type metricEvent struct {
+	name   string
+	labels e.MetricLabels
+}
+
+type eventMetrics struct {
+	counters   []metricEvent
+	histograms []metricEvent
+}
+
+func (m *eventMetrics) IncCounter(name string, labels e.MetricLabels) {
+	m.counters = append(m.counters, metricEvent{name, labels})
+}
+
+func (m *eventMetrics) ObserveHistogram(name string, value float64, labels e.MetricLabels) {
+	m.histograms = append(m.histograms, metricEvent{name, labels})
+}
+
+func observe(t *testing.T) *eventMetrics {
+	m := &eventMetrics{}
+	t.Cleanup(e.Observe(m))
+	return m
+}
+
+func TestObserve(t *testing.T) {
+	errMetric := codedError{code: "not_found"}
+	t.Run("should count thrown and escaped errors", func(t *testing.T) {
+		m := observe(t)
+		load := func() (err error) {
+			defer e.HandleErr(&err)
+			e.Try(errMetric).Err()
+			return nil
+		}
+		require.Error(t, load())
+		require.Len(t, m.counters, 2)
+		assert.Equal(t, e.MetricErrors, m.counters[0].name)
+		assert.Equal(t, e.OutcomeThrown, m.counters[0].labels.Outcome)
+		assert.Equal(t, "not_found", m.counters[0].labels.Code)
+		assert.Regexp(t, `^\w+/metrics_test.go:\d+$`, m.counters[0].labels.Site)
+		assert.Equal(t, e.OutcomeEscaped, m.counters[1].labels.Outcome)
+		assert.Equal(t, m.counters[0].labels.Site, m.counters[1].labels.Site)
+		require.Len(t, m.histograms, 1)
+		assert.Equal(t, e.MetricUnwindSeconds, m.histograms[0].name)
+	})
+	t.Run("should count recovered errors", func(t *testing.T) {
+		m := observe(t)
+		func() {
+			defer e.Handle(nil, func(error) error { return nil })
+			e.Try(errMetric).Err()
+		}()
+		require.Len(t, m.counters, 2)
+		assert.Equal(t, e.OutcomeRecovered, m.counters[1].labels.Outcome)
+	})
+	t.Run("should count errors swallowed by a handler", func(t *testing.T) {
+		m := observe(t)
+		e.Try(errMetric).Err(func(error) error { return nil })
+		require.Len(t, m.counters, 1)
+		assert.Equal(t, e.OutcomeHandled, m.counters[0].labels.Outcome)
+		assert.Regexp(t, `^\w+/metrics_test.go:\d+$`, m.counters[0].labels.Site)
+	})
+	t.Run("should count errors replaced by a fallback", func(t *testing.T) {
+		m := observe(t)
+		v := e.Try1(0, errMetric).Fallback(func(error) int { return 1 })
+		assert.Equal(t, 1, v)
+		e.Try1(0, nil).Fallback(func(error) int { return 1 })
+		require.Len(t, m.counters, 1)
+		assert.Equal(t, e.OutcomeFallback, m.counters[0].labels.Outcome)
+	})
+	t.Run("should not report after restore", func(t *testing.T) {
+		m := &eventMetrics{}
+		e.Observe(m)()
+		errlesstest.CaptureThrow(func() { e.Try(errMetric).Err() })
+		assert.Empty(t, m.counters)
+	})
+}
+
+func TestErrorCode(t *testing.T) {
+	assert.Equal(t, "conflict", e.ErrorCode(fmt.Errorf("update: %w", codedError{code: "conflict"})))
+	assert.Equal(t, "", e.ErrorCode(errors.New("plain")))
+}
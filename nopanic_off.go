@@ -0,0 +1,9 @@
+//go:build !errless_nopanic
+
+package errless
+
+// NewScope returns nil without the errless_nopanic build tag, so the checks made in
+// the scope throw their errors.
+func NewScope() *Scope {
+	return nil
+}
@@ -0,0 +1,58 @@
+// Package otel reports errors handled by errless to OpenTelemetry spans.
+//
+//	restore := otel.Install()
+//	defer restore()
+//
+//	func load(ctx context.Context, id string) (u User, err error) {
+//		defer errless.HandleCtx(ctx, &err, nil)
+//		...
+//	}
+package otel
+
+import (
+	"context"
+
+	"github.com/mfatihercik/errless"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracer is an errless.Tracer that finds the OpenTelemetry span of a context.
+type Tracer struct {
+	// StackTrace adds the stack trace to the exception events of the spans.
+	StackTrace bool
+}
+
+// Install sets a Tracer as the errless tracer. It returns a function that restores the previous tracer.
+func Install() (restore func()) {
+	return errless.SetTracer(Tracer{})
+}
+
+// SpanFromContext implements errless.Tracer.
+func (t Tracer) SpanFromContext(ctx context.Context) errless.SpanRecorder {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return nil
+	}
+	return spanRecorder{span: span, stackTrace: t.StackTrace}
+}
+
+type spanRecorder struct {
+	span       trace.Span
+	stackTrace bool
+}
+
+func (s spanRecorder) RecordError(err error) {
+	s.span.RecordError(err, trace.WithStackTrace(s.stackTrace))
+}
+
+func (s spanRecorder) SetStatus(code errless.StatusCode, description string) {
+	switch code {
+	case errless.StatusError:
+		s.span.SetStatus(codes.Error, description)
+	case errless.StatusOK:
+		s.span.SetStatus(codes.Ok, description)
+	default:
+		s.span.SetStatus(codes.Unset, description)
+	}
+}
@@ -0,0 +1,44 @@
+//go:build test
+
+package otel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mfatihercik/errless"
+	"github.com/mfatihercik/errless/errlesstest"
+	errlessotel "github.com/mfatihercik/errless/otel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var errLoad = errors.New("load failed")
+
+func TestTracer(t *testing.T) {
+	t.Cleanup(errlessotel.Install())
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	ctx, span := provider.Tracer("test").Start(context.Background(), "load")
+	err := errlesstest.CaptureThrow(func() {
+		errless.Try(errLoad).Err(errless.RecordSpan(ctx))
+	})
+	span.End()
+
+	assert.ErrorIs(t, err, errLoad)
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "load failed", spans[0].Status().Description)
+	require.Len(t, spans[0].Events(), 1)
+	assert.Equal(t, "exception", spans[0].Events()[0].Name)
+}
+
+func TestTracerIgnoresContextWithoutSpan(t *testing.T) {
+	assert.Nil(t, errlessotel.Tracer{}.SpanFromContext(context.Background()))
+}
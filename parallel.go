@@ -0,0 +1,113 @@
+package errless
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/mfatihercik/errless/internal/throw"
+)
+
+// MapParallel calls fn for each item, running at most limit calls at the same time, and
+// returns the results in the order of the items. A limit below 1 runs every call at once.
+//
+// fn may throw. The first error thrown cancels the context passed to the other calls, and
+// no further call is started. Once the running calls return, MapParallel throws the first
+// error, joined with the other errors thrown that are not caused by the cancellation, in
+// the goroutine of its caller, so a deferred Handle there catches it. If ctx is done before
+// every call is started, MapParallel throws its cause.
+func MapParallel[T, R any](ctx context.Context, items []T, limit int, fn func(context.Context, T) R) []R {
+	results := make([]R, len(items))
+	runParallel(ctx, len(items), limit, func(ctx context.Context, i int) {
+		results[i] = fn(ctx, items[i])
+	})
+	return results
+}
+
+// ForEachParallel calls fn for each item like MapParallel.
+func ForEachParallel[T any](ctx context.Context, items []T, limit int, fn func(context.Context, T)) {
+	runParallel(ctx, len(items), limit, func(ctx context.Context, i int) {
+		fn(ctx, items[i])
+	})
+}
+
+// parallel holds the outcome of the calls of runParallel.
+type parallel struct {
+	cancel context.CancelCauseFunc
+	mu     sync.Mutex
+	errs   []error
+	panic  any // the first panic that was not raised by errless
+	failed bool
+}
+
+func runParallel(parent context.Context, n, limit int, fn func(context.Context, int)) {
+	if limit < 1 || limit > n {
+		limit = n
+	}
+	ctx, cancel := context.WithCancelCause(parent)
+	defer cancel(nil)
+	p := &parallel{cancel: cancel}
+	sem := make(chan struct{}, max(limit, 1))
+	var wg sync.WaitGroup
+	started := 0
+launch:
+	for ; started < n; started++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break launch
+		}
+		if ctx.Err() != nil {
+			<-sem
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			defer p.recover(ctx)
+			fn(ctx, i)
+		}(started)
+	}
+	wg.Wait()
+
+	if p.panic != nil {
+		panic(p.panic)
+	}
+	if len(p.errs) > 0 {
+		rethrow(join(p.errs))
+	}
+	if started < n {
+		Throw(context.Cause(parent))
+	}
+}
+
+// recover records the error thrown by a call and cancels the other calls.
+func (p *parallel) recover(ctx context.Context) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	exp, ok := r.(throw.Exception)
+	if !ok {
+		if p.panic == nil {
+			p.panic = r
+		}
+		p.cancel(errParallelPanic)
+		return
+	}
+	// The error is thrown again in the caller, where it is observed when it is recovered.
+	recoverTrail(&exp, "errless.MapParallel")
+	if p.failed && (errors.Is(exp.Err, context.Canceled) || errors.Is(exp.Err, context.Cause(ctx))) {
+		return
+	}
+	if !p.failed {
+		p.failed = true
+		p.cancel(exp.Err)
+	}
+	p.errs = append(p.errs, exp.Err)
+}
+
+var errParallelPanic = errors.New("errless: a parallel call panicked")
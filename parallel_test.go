@@ -0,0 +1,114 @@
+//go:build test
+
+package errless_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	e "github.com/mfatihercik/errless"
+	"github.com/mfatihercik/errless/errlesstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errFetch = errors.New("fetch failed")
+
+func fetch(ctx context.Context, id int) string {
+	if id < 0 {
+		e.Throw(fmt.Errorf("fetch %d: %w", id, errFetch))
+	}
+	return fmt.Sprint("item-", id)
+}
+
+func TestMapParallel(t *testing.T) {
+	t.Run("should return the results in order", func(t *testing.T) {
+		got := e.MapParallel(context.Background(), []int{1, 2, 3, 4}, 2, fetch)
+		assert.Equal(t, []string{"item-1", "item-2", "item-3", "item-4"}, got)
+	})
+	t.Run("should bound the concurrency", func(t *testing.T) {
+		var running, peak atomic.Int32
+		e.ForEachParallel(context.Background(), make([]int, 20), 3, func(ctx context.Context, _ int) {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			running.Add(-1)
+		})
+		assert.LessOrEqual(t, peak.Load(), int32(3))
+	})
+	t.Run("should rethrow in the caller so Handle catches it", func(t *testing.T) {
+		load := func() (res []string, err error) {
+			defer e.HandleErr(&err)
+			return e.MapParallel(context.Background(), []int{1, -2, 3}, 2, fetch), nil
+		}
+		_, err := load()
+		assert.ErrorIs(t, err, errFetch)
+		assert.EqualError(t, err, "fetch -2: fetch failed")
+	})
+	t.Run("should apply the default handlers and metrics once", func(t *testing.T) {
+		errlesstest.DefaultHandlers(t, prefix("svc"))
+		m := observe(t)
+		err := errlesstest.CaptureThrow(func() {
+			e.MapParallel(context.Background(), []int{-1}, 1, fetch)
+		})
+		assert.EqualError(t, err, "svc: fetch -1: fetch failed")
+		require.Len(t, m.counters, 1)
+		assert.Equal(t, e.OutcomeThrown, m.counters[0].labels.Outcome)
+	})
+	t.Run("should cancel the other calls", func(t *testing.T) {
+		var started atomic.Int32
+		err := errlesstest.CaptureThrow(func() {
+			e.ForEachParallel(context.Background(), []int{-1, 1, 2, 3, 4, 5}, 2, func(ctx context.Context, id int) {
+				started.Add(1)
+				if id < 0 {
+					e.Throw(errFetch)
+				}
+				<-ctx.Done()
+				e.Throw(ctx.Err())
+			})
+		})
+		assert.Equal(t, errFetch, err)
+		assert.Less(t, started.Load(), int32(6))
+	})
+	t.Run("should join independent errors", func(t *testing.T) {
+		release := make(chan struct{})
+		var arrived atomic.Int32
+		err := errlesstest.CaptureThrow(func() {
+			e.ForEachParallel(context.Background(), []int{-1, -2}, 2, func(ctx context.Context, id int) {
+				if arrived.Add(1) == 2 {
+					close(release)
+				}
+				<-release
+				fetch(ctx, id)
+			})
+		})
+		assert.ErrorIs(t, err, errFetch)
+		assert.Len(t, e.Flatten(err), 2)
+	})
+	t.Run("should throw the cause of a done context", func(t *testing.T) {
+		ctx, cancel := context.WithCancelCause(context.Background())
+		errStop := errors.New("stop")
+		cancel(errStop)
+		err := errlesstest.CaptureThrow(func() {
+			e.MapParallel(ctx, []int{1, 2}, 1, fetch)
+		})
+		assert.ErrorIs(t, err, errStop)
+	})
+	t.Run("should repanic other panics in the caller", func(t *testing.T) {
+		assert.PanicsWithValue(t, "boom", func() {
+			e.ForEachParallel(context.Background(), []int{1}, 1, func(context.Context, int) { panic("boom") })
+		})
+	})
+	t.Run("should handle no items", func(t *testing.T) {
+		assert.Empty(t, e.MapParallel(context.Background(), []int(nil), 4, fetch))
+	})
+}
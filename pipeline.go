@@ -0,0 +1,188 @@
+package errless
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/mfatihercik/errless/internal/throw"
+)
+
+// Policy decides what a Pipeline does with the errors thrown by its stages.
+type Policy int
+
+const (
+	// FailFast stops every stage at the first error, which Wait returns.
+	FailFast Policy = iota
+	// Skip drops the items whose stage threw, and goes on.
+	Skip
+	// Collect drops the items whose stage threw, goes on, and joins the errors for Wait.
+	Collect
+	// Route drops the items whose stage threw, goes on, and sends the errors to Errors.
+	Route
+)
+
+// Pipeline connects stages that run on their own goroutines and pass items through channels.
+// A stage function calls Try and Throw like any other errless function; the error it throws
+// only fails the item it was called for, and is then handled by the policy of the pipeline.
+//
+//	p := errless.Pipe(ctx, errless.FailFast)
+//	ids := errless.Source(p, userIDs)
+//	users := errless.Stage(p, ids, func(id string) User { return errless.Try1(load(id)).Err() }, 4)
+//	for u := range users {
+//		...
+//	}
+//	p.Err() // throws the first error
+type Pipeline struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	policy Policy
+	wg     sync.WaitGroup
+	errs   chan error
+	close  sync.Once
+
+	mu        sync.Mutex
+	collected []error
+}
+
+// Pipe returns a pipeline that handles the errors of its stages with policy.
+// The pipeline stops when ctx is done.
+func Pipe(ctx context.Context, policy Policy) *Pipeline {
+	ctx, cancel := context.WithCancelCause(ctx)
+	p := &Pipeline{ctx: ctx, cancel: cancel, policy: policy}
+	if policy == Route {
+		p.errs = make(chan error)
+	}
+	return p
+}
+
+// Context returns the context of the pipeline, which is canceled when it fails fast.
+func (p *Pipeline) Context() context.Context {
+	return p.ctx
+}
+
+// Errors returns the channel the Route policy sends errors to. The stages wait for the errors
+// to be received, and the channel is closed by Wait. It is nil for the other policies.
+func (p *Pipeline) Errors() <-chan error {
+	return p.errs
+}
+
+// Wait waits for the stages to finish, and returns the first error with FailFast, or the
+// errors joined with Collect. It also returns the cause of ctx if it was done before.
+func (p *Pipeline) Wait() error {
+	p.wg.Wait()
+	p.close.Do(func() {
+		if p.errs != nil {
+			close(p.errs)
+		}
+	})
+	p.cancel(nil)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.policy == Collect && len(p.collected) > 0 {
+		return errors.Join(p.collected...)
+	}
+	if err := context.Cause(p.ctx); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
+
+// Err waits for the stages to finish and throws the error returned by Wait with the handlers.
+// The registered handlers are not applied again, as they were when the stages threw.
+func (p *Pipeline) Err(handles ...HandlerFunc) {
+	rethrow(p.Wait(), handles...)
+}
+
+// fail handles an error thrown by a stage.
+func (p *Pipeline) fail(err error) {
+	switch p.policy {
+	case FailFast:
+		p.cancel(err)
+	case Collect:
+		p.mu.Lock()
+		p.collected = append(p.collected, err)
+		p.mu.Unlock()
+	case Route:
+		select {
+		case p.errs <- err:
+		case <-p.ctx.Done():
+		}
+	}
+}
+
+// Source returns a channel of the items, closed after the last one or when the pipeline stops.
+func Source[T any](p *Pipeline, items []T) <-chan T {
+	out := make(chan T)
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer close(out)
+		for _, item := range items {
+			select {
+			case out <- item:
+			case <-p.ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Stage runs fn for the items of in on workers goroutines, and returns a channel of the results.
+// The results are not in the order of the items. The channel is closed when in is closed and
+// drained, or when the pipeline stops.
+func Stage[In, Out any](p *Pipeline, in <-chan In, fn func(In) Out, workers int) <-chan Out {
+	if workers < 1 {
+		workers = 1
+	}
+	out := make(chan Out)
+	var stage sync.WaitGroup
+	stage.Add(workers)
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer p.wg.Done()
+			defer stage.Done()
+			for {
+				var item In
+				var ok bool
+				select {
+				case item, ok = <-in:
+				case <-p.ctx.Done():
+					return
+				}
+				if !ok {
+					return
+				}
+				result, err := runItem(fn, item)
+				if err != nil {
+					p.fail(err)
+					continue
+				}
+				select {
+				case out <- result:
+				case <-p.ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		stage.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// runItem calls fn and returns the error it throws.
+func runItem[In, Out any](fn func(In) Out, item In) (result Out, err error) {
+	defer func() {
+		if exp := throw.Recover(recover()); exp != nil {
+			recoverTrail(exp, "errless.Stage")
+			observeRecover(exp, exp.Err)
+			err = exp.Err
+		}
+	}()
+	return fn(item), nil
+}
@@ -0,0 +1,101 @@
+//go:build test
+
+package errless_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+
+	e "github.com/mfatihercik/errless"
+	"github.com/mfatihercik/errless/errlesstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseStage(p *e.Pipeline, in []string) <-chan int {
+	parsed := e.Stage(p, e.Source(p, in), func(s string) int {
+		return e.Try1(strconv.Atoi(s)).ErrMessage("parse " + s)
+	}, 3)
+	return e.Stage(p, parsed, func(n int) int { return n * 10 }, 2)
+}
+
+func drain[T any](ch <-chan T) []T {
+	var all []T
+	for v := range ch {
+		all = append(all, v)
+	}
+	return all
+}
+
+func TestPipeline(t *testing.T) {
+	t.Run("should pass every item through the stages", func(t *testing.T) {
+		p := e.Pipe(context.Background(), e.FailFast)
+		got := drain(parseStage(p, []string{"1", "2", "3"}))
+		require.NoError(t, p.Wait())
+		slices.Sort(got)
+		assert.Equal(t, []int{10, 20, 30}, got)
+	})
+	t.Run("should stop every stage on the first error with FailFast", func(t *testing.T) {
+		p := e.Pipe(context.Background(), e.FailFast)
+		items := make([]string, 1000)
+		for i := range items {
+			items[i] = strconv.Itoa(i)
+		}
+		items[5] = "x"
+		got := drain(parseStage(p, items))
+		err := p.Wait()
+		assert.EqualError(t, err, `parse x - error: strconv.Atoi: parsing "x": invalid syntax`)
+		assert.Less(t, len(got), len(items)-1)
+		assert.ErrorIs(t, context.Cause(p.Context()), err)
+		assert.EqualError(t, errlesstest.CaptureThrow(func() { p.Err() }), err.Error())
+	})
+	t.Run("should apply the default handlers once", func(t *testing.T) {
+		errlesstest.DefaultHandlers(t, prefix("svc"))
+		p := e.Pipe(context.Background(), e.FailFast)
+		drain(parseStage(p, []string{"x"}))
+		err := errlesstest.CaptureThrow(func() { p.Err(prefix("site")) })
+		assert.EqualError(t, err, `site: svc: parse x - error: strconv.Atoi: parsing "x": invalid syntax`)
+	})
+	t.Run("should drop failed items with Skip", func(t *testing.T) {
+		p := e.Pipe(context.Background(), e.Skip)
+		got := drain(parseStage(p, []string{"1", "x", "3"}))
+		assert.NoError(t, p.Wait())
+		slices.Sort(got)
+		assert.Equal(t, []int{10, 30}, got)
+	})
+	t.Run("should join the errors with Collect", func(t *testing.T) {
+		p := e.Pipe(context.Background(), e.Collect)
+		got := drain(parseStage(p, []string{"1", "x", "y"}))
+		err := p.Wait()
+		assert.Equal(t, []int{10}, got)
+		assert.Len(t, e.Flatten(err), 2)
+	})
+	t.Run("should send the errors to Errors with Route", func(t *testing.T) {
+		p := e.Pipe(context.Background(), e.Route)
+		var routed []error
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for err := range p.Errors() {
+				routed = append(routed, err)
+			}
+		}()
+		got := drain(parseStage(p, []string{"1", "x", "3"}))
+		assert.NoError(t, p.Wait())
+		<-done
+		assert.Len(t, got, 2)
+		require.Len(t, routed, 1)
+		assert.ErrorContains(t, routed[0], "parse x")
+	})
+	t.Run("should stop when the context is canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancelCause(context.Background())
+		errStop := errors.New("stop")
+		cancel(errStop)
+		p := e.Pipe(ctx, e.FailFast)
+		drain(parseStage(p, []string{"1", "2"}))
+		assert.ErrorIs(t, p.Wait(), errStop)
+	})
+}
@@ -0,0 +1,38 @@
+package errless
+
+// DefaultPublicMessage is the message PublicMessageOf returns for errors without a public message.
+const DefaultPublicMessage = "internal error"
+
+// PublicError is an error with a message that is safe to show to users. Error returns
+// the internal detail of the wrapped error, for logs.
+type PublicError struct {
+	Err     error
+	Message string
+}
+
+func (e *PublicError) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Err.Error()
+}
+
+// PublicMessage returns the message that is safe to show to users.
+func (e *PublicError) PublicMessage() string { return e.Message }
+
+func (e *PublicError) Unwrap() error { return e.Err }
+
+// Public returns a handler that wraps the error in a PublicError with message.
+// It is the same as AsUserError.
+func Public(message string) HandlerFunc {
+	return AsUserError(message)
+}
+
+// PublicMessageOf returns the outermost public message in the chain of err, or
+// DefaultPublicMessage if err has none or is marked with AsInternal.
+func PublicMessageOf(err error) string {
+	if message, ok := UserMessage(err); ok {
+		return message
+	}
+	return DefaultPublicMessage
+}
@@ -0,0 +1,61 @@
+//go:build test
+
+package errless_test
+
+import (
+	"fmt"
+	"testing"
+
+	e "github.com/mfatihercik/errless"
+	"github.com/mfatihercik/errless/errlesstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublic(t *testing.T) {
+	t.Run("should keep the internal detail out of the public message", func(t *testing.T) {
+		err := e.Public("could not load the account")(errDB)
+		var public *e.PublicError
+		require.ErrorAs(t, err, &public)
+		assert.Equal(t, "could not load the account", public.PublicMessage())
+		assert.EqualError(t, err, errDB.Error())
+		assert.ErrorIs(t, err, errDB)
+	})
+	t.Run("should return the outermost public message", func(t *testing.T) {
+		err := e.Public("request failed")(fmt.Errorf("load: %w", e.Public("account not found")(errDB)))
+		assert.Equal(t, "request failed", e.PublicMessageOf(err))
+		assert.Equal(t, "account not found", e.PublicMessageOf(e.AsRetryable()(e.AsUserError("account not found")(errDB))))
+	})
+	t.Run("should return the default message", func(t *testing.T) {
+		assert.Equal(t, e.DefaultPublicMessage, e.PublicMessageOf(errDB))
+		assert.Equal(t, e.DefaultPublicMessage, e.PublicMessageOf(e.AsInternal()(e.Public("account not found")(errDB))))
+	})
+	t.Run("should expose the message of user errors", func(t *testing.T) {
+		err := e.AsUserError("account not found")(errDB)
+		assert.Equal(t, "account not found", err.(interface{ PublicMessage() string }).PublicMessage())
+	})
+}
+
+func TestErrPublic(t *testing.T) {
+	const msg = "could not load the account"
+	throws := map[string]func(){
+		"Try":  func() { e.Try(errDB).ErrPublic(msg) },
+		"Try1": func() { e.Try1(1, errDB).ErrPublic(msg) },
+		"Try2": func() { e.Try2(1, 2, errDB).ErrPublic(msg) },
+		"Try3": func() { e.Try3(1, 2, 3, errDB).ErrPublic(msg) },
+		"Try4": func() { e.Try4(1, 2, 3, 4, errDB).ErrPublic(msg) },
+		"Try5": func() { e.Try5(1, 2, 3, 4, 5, errDB).ErrPublic(msg) },
+	}
+	for name, fn := range throws {
+		t.Run(name, func(t *testing.T) {
+			err := errlesstest.CaptureThrow(fn)
+			assert.ErrorIs(t, err, errDB)
+			assert.Equal(t, msg, e.PublicMessageOf(err))
+		})
+	}
+	t.Run("should return the values without error", func(t *testing.T) {
+		a, b := e.Try2(1, "b", nil).ErrPublic(msg)
+		assert.Equal(t, 1, a)
+		assert.Equal(t, "b", b)
+	})
+}
@@ -0,0 +1,118 @@
+package errless
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mfatihercik/errless/internal/throw"
+)
+
+// handlerRegistry is an immutable snapshot of the registered handlers.
+// It is replaced as a whole, so Throw can read it without locking.
+type handlerRegistry struct {
+	defaults []HandlerFunc
+	packages map[string][]HandlerFunc
+}
+
+var (
+	registry   atomic.Pointer[handlerRegistry]
+	registryMu sync.Mutex // serializes updates of registry
+)
+
+// SetDefaultHandlers registers the handlers Throw applies to every error, after the
+// handlers of the call site and of the calling package.
+// It returns a function that restores the previously registered handlers.
+//
+// The handlers are shared by all goroutines of the process. It is safe to register
+// handlers while other goroutines throw; a Throw sees either the old or the new handlers.
+func SetDefaultHandlers(handlers ...HandlerFunc) (restore func()) {
+	var previous []HandlerFunc
+	updateRegistry(func(r *handlerRegistry) {
+		previous, r.defaults = r.defaults, clone(handlers)
+	})
+	return func() {
+		updateRegistry(func(r *handlerRegistry) {
+			r.defaults = previous
+		})
+	}
+}
+
+// PackageHandlers registers handlers for the errors thrown from one package.
+type PackageHandlers struct {
+	name string
+}
+
+// Package returns the handler registration for a package. The name is either the
+// full import path or its last elements, e.g. "billing" or "app/billing". When
+// several registered names match a package, only the longest one applies.
+func Package(name string) PackageHandlers {
+	return PackageHandlers{name: strings.Trim(name, "/")}
+}
+
+// Handlers registers the handlers Throw applies to errors thrown from the package,
+// after the handlers of the call site and before the default handlers.
+// It returns a function that restores the previously registered handlers.
+func (p PackageHandlers) Handlers(handlers ...HandlerFunc) (restore func()) {
+	var previous []HandlerFunc
+	updateRegistry(func(r *handlerRegistry) {
+		previous = r.packages[p.name]
+		r.packages[p.name] = clone(handlers)
+	})
+	return func() {
+		updateRegistry(func(r *handlerRegistry) {
+			r.packages[p.name] = previous
+		})
+	}
+}
+
+func updateRegistry(update func(r *handlerRegistry)) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	next := &handlerRegistry{packages: map[string][]HandlerFunc{}}
+	if current := registry.Load(); current != nil {
+		next.defaults = current.defaults
+		for name, handlers := range current.packages {
+			next.packages[name] = handlers
+		}
+	}
+	update(next)
+	for name, handlers := range next.packages {
+		if len(handlers) == 0 {
+			delete(next.packages, name)
+		}
+	}
+	if len(next.defaults) == 0 && len(next.packages) == 0 {
+		next = nil
+	}
+	registry.Store(next)
+}
+
+// applyRegistered applies the handlers registered for the site's package and the default handlers.
+func applyRegistered(err error, site throw.Site, t *trailRecorder) error {
+	r := registry.Load()
+	if r == nil {
+		return err
+	}
+	if handlers := r.forPackage(site.Package()); handlers != nil {
+		if err = applyHandlers(err, handlers, t); err == nil {
+			return nil
+		}
+	}
+	return applyHandlers(err, r.defaults, t)
+}
+
+// forPackage returns the handlers of the longest registered name matching pkg.
+func (r *handlerRegistry) forPackage(pkg string) []HandlerFunc {
+	var match string
+	for name := range r.packages {
+		if (pkg == name || strings.HasSuffix(pkg, "/"+name)) && len(name) > len(match) {
+			match = name
+		}
+	}
+	return r.packages[match]
+}
+
+func clone(handlers []HandlerFunc) []HandlerFunc {
+	return append([]HandlerFunc(nil), handlers...)
+}
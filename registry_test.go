@@ -0,0 +1,104 @@
+//go:build test
+
+package errless_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	e "github.com/mfatihercik/errless"
+	"github.com/mfatihercik/errless/errlesstest"
+	"github.com/stretchr/testify/assert"
+)
+
+var errRegistry = errors.New("registry error")
+
+func prefix(p string) e.HandlerFunc {
+	return func(err error) error {
+		return fmt.Errorf("%s: %w", p, err)
+	}
+}
+
+func TestDefaultHandlers(t *testing.T) {
+	t.Run("should apply default handlers after the call site handlers", func(t *testing.T) {
+		errlesstest.DefaultHandlers(t, prefix("default"))
+		err := errlesstest.CaptureThrow(func() {
+			e.Throw(errRegistry, prefix("site"))
+		})
+		assert.EqualError(t, err, "default: site: registry error")
+		assert.ErrorIs(t, err, errRegistry)
+	})
+	t.Run("should not throw when a default handler returns nil", func(t *testing.T) {
+		errlesstest.DefaultHandlers(t, func(err error) error { return nil })
+		errlesstest.AssertNoThrow(t, func() {
+			e.Try(errRegistry).Err()
+		})
+	})
+	t.Run("should not call default handlers when the call site handles the error", func(t *testing.T) {
+		errlesstest.DefaultHandlers(t, func(err error) error {
+			t.Fatal("shouldn't be called")
+			return err
+		})
+		errlesstest.AssertNoThrow(t, func() {
+			e.Throw(errRegistry, func(err error) error { return nil })
+		})
+	})
+	t.Run("should restore the previous handlers", func(t *testing.T) {
+		restoreOuter := e.SetDefaultHandlers(prefix("outer"))
+		restoreInner := e.SetDefaultHandlers(prefix("inner"))
+		assert.EqualError(t, errlesstest.CaptureThrow(func() { e.Throw(errRegistry) }), "inner: registry error")
+		restoreInner()
+		assert.EqualError(t, errlesstest.CaptureThrow(func() { e.Throw(errRegistry) }), "outer: registry error")
+		restoreOuter()
+		assert.EqualError(t, errlesstest.CaptureThrow(func() { e.Throw(errRegistry) }), "registry error")
+	})
+}
+
+func TestPackageHandlers(t *testing.T) {
+	t.Run("should apply package handlers before default handlers", func(t *testing.T) {
+		errlesstest.DefaultHandlers(t, prefix("default"))
+		errlesstest.PackageHandlers(t, "errless_test", prefix("package"))
+		err := errlesstest.CaptureThrow(func() {
+			e.Try(errRegistry).ErrMessage("load")
+		})
+		assert.EqualError(t, err, "default: package: load - error: registry error")
+	})
+	t.Run("should match the full import path", func(t *testing.T) {
+		errlesstest.PackageHandlers(t, "github.com/mfatihercik/errless_test", prefix("package"))
+		assert.EqualError(t, errlesstest.CaptureThrow(func() { e.Throw(errRegistry) }), "package: registry error")
+	})
+	t.Run("should prefer the longest matching name", func(t *testing.T) {
+		errlesstest.PackageHandlers(t, "errless_test", prefix("short"))
+		errlesstest.PackageHandlers(t, "mfatihercik/errless_test", prefix("long"))
+		assert.EqualError(t, errlesstest.CaptureThrow(func() { e.Throw(errRegistry) }), "long: registry error")
+	})
+	t.Run("should ignore handlers of other packages", func(t *testing.T) {
+		errlesstest.PackageHandlers(t, "billing", prefix("billing"))
+		errlesstest.PackageHandlers(t, "less_test", prefix("partial"))
+		assert.EqualError(t, errlesstest.CaptureThrow(func() { e.Throw(errRegistry) }), "registry error")
+	})
+}
+
+func TestHandlersConcurrentUse(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				e.SetDefaultHandlers(prefix("default"))()
+				e.Package("errless_test").Handlers(prefix("package"))()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				err := errlesstest.CaptureThrow(func() { e.Throw(errRegistry) })
+				assert.ErrorIs(t, err, errRegistry)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,89 @@
+package errless
+
+import "github.com/mfatihercik/errless/internal/throw"
+
+// Scope collects the error of the checks made in it instead of throwing it, for code
+// that runs where panic based control flow is not allowed. A check that fails in a
+// scope records its error, if it is the first one, and returns zero values; the code
+// goes on and reads the error from the scope at the end.
+//
+// NewScope returns nil unless the errless_nopanic build tag is set, and the checks
+// made in a nil scope throw as usual, so the same code runs in both modes:
+//
+//	func Load(path string) (cfg Config, err error) {
+//		s := errless.NewScope()
+//		defer s.Handle(&err)
+//		data := errless.Try1(os.ReadFile(path)).In(s).ErrMessage("read config")
+//		s.Try(json.Unmarshal(data, &cfg)).ErrMessage("parse config")
+//		return cfg, s.Err()
+//	}
+//
+// The build tag only changes NewScope. Throw, ThrowN, the checks not made in a scope,
+// and the helpers that throw, such as MapParallel and Pipeline.Err, still panic with it,
+// so code that must not panic has to make every check in a scope: s.Throw replaces Throw,
+// and TryN(...).In(s).Err() replaces ThrowN.
+//
+// A Scope is meant to be used by a single goroutine.
+type Scope struct {
+	exp *throw.Exception
+}
+
+// throw records err in s after applying the handlers of Throw to it, and reports
+// whether it was recorded. It throws err if s is nil.
+func (s *Scope) throw(err error, handles ...HandlerFunc) bool {
+	return s.check(err, handles, false)
+}
+
+// check is throw for an error that may have been thrown before, see raise.
+func (s *Scope) check(err error, handles []HandlerFunc, rethrown bool) bool {
+	if err == nil {
+		return false
+	}
+	exp, thrown := raise(err, handles, rethrown)
+	if thrown && s == nil {
+		panic(exp)
+	}
+	if thrown && s.exp == nil {
+		s.exp = &exp
+	}
+	return thrown
+}
+
+// Throw records err in s after applying handlers to it, like Throw does before throwing.
+// It throws err if s is nil.
+func (s *Scope) Throw(err error, handles ...HandlerFunc) {
+	s.throw(err, handles...)
+}
+
+// Try starts a check in s, like Try(err).In(s).
+func (s *Scope) Try(err error) Params0 {
+	return Params0{err: injectAtSite(err), scope: s}
+}
+
+// Err returns the first error recorded in s, or nil.
+func (s *Scope) Err() error {
+	if s == nil || s.exp == nil {
+		return nil
+	}
+	return s.exp.Err
+}
+
+// Failed reports whether an error was recorded in s, so the code can return early.
+func (s *Scope) Failed() bool {
+	return s.Err() != nil
+}
+
+// Handle is used like HandleErr, and also sets namedErr to the error recorded in s
+// when nothing was thrown and the function did not return an error. It works as
+// HandleErr on a nil scope.
+func (s *Scope) Handle(namedErr *error) {
+	exp := recoverException(recover())
+	if exp == nil && s != nil && namedErr != nil && *namedErr == nil {
+		exp = s.exp
+	}
+	if exp != nil && namedErr != nil {
+		recoverTrail(exp, "errless.Scope.Handle")
+		observeRecover(exp, exp.Err)
+		*namedErr = exp.Err
+	}
+}
@@ -0,0 +1,51 @@
+//go:build test && errless_nopanic
+
+package errless_test
+
+import (
+	"testing"
+
+	e "github.com/mfatihercik/errless"
+	"github.com/mfatihercik/errless/errlesstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewScopeRecords(t *testing.T) {
+	t.Run("should record instead of panicking", func(t *testing.T) {
+		s := e.NewScope()
+		require.NotNil(t, s)
+		assert.NotPanics(t, func() { s.Try(errClosed).ErrMessage("read") })
+		assert.EqualError(t, s.Err(), "read - error: closed")
+
+		s = e.NewScope()
+		assert.NotPanics(t, func() { s.Throw(errClosed, e.Message("close")) })
+		assert.EqualError(t, s.Err(), "close - error: closed")
+	})
+	t.Run("should return zero values from every ParamsN", func(t *testing.T) {
+		s := e.NewScope()
+		assert.NotPanics(t, func() {
+			a := e.Try1(1, errClosed).In(s).Err()
+			b1, b2 := e.Try2(1, "b", errClosed).In(s).Err()
+			c1, c2, c3 := e.Try3(1, "b", true, errClosed).In(s).Err()
+			d1, d2, d3, d4 := e.Try4(1, "b", true, 2.0, errClosed).In(s).Err()
+			e1, e2, e3, e4, e5 := e.Try5(1, "b", true, 2.0, 'r', errClosed).In(s).Err()
+			assert.Zero(t, a)
+			assert.Zero(t, b1+c1+d1+e1)
+			assert.Empty(t, b2+c2+d2+e2)
+			assert.False(t, c3 || d3 || e3)
+			assert.Zero(t, d4+e4)
+			assert.Zero(t, e5)
+		})
+		assert.ErrorIs(t, s.Err(), errClosed)
+	})
+	t.Run("should set the named error of the function", func(t *testing.T) {
+		sum, err := parsePair(e.NewScope(), "1", "x")
+		assert.Equal(t, 1, sum)
+		assert.EqualError(t, err, `parse b - error: strconv.Atoi: parsing "x": invalid syntax`)
+	})
+	t.Run("should still throw outside a scope", func(t *testing.T) {
+		errlesstest.AssertThrowsIs(t, errClosed, func() { e.Try(errClosed).Err() })
+		errlesstest.AssertThrowsIs(t, errClosed, func() { e.Throw(errClosed) })
+	})
+}
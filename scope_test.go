@@ -0,0 +1,88 @@
+//go:build test
+
+package errless_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	e "github.com/mfatihercik/errless"
+	"github.com/mfatihercik/errless/errlesstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errClosed = errors.New("closed")
+
+func parsePair(s *e.Scope, a, b string) (sum int, err error) {
+	defer s.Handle(&err)
+	x := e.Try1(strconv.Atoi(a)).In(s).ErrMessage("parse a")
+	y := e.Try1(strconv.Atoi(b)).In(s).ErrMessage("parse b")
+	return x + y, nil
+}
+
+func TestScope(t *testing.T) {
+	t.Run("should record the first error and return zero values", func(t *testing.T) {
+		s := &e.Scope{}
+		n, word := e.Try2(7, "seven", errClosed).In(s).Err()
+		assert.Zero(t, n)
+		assert.Zero(t, word)
+		e.Try(errors.New("second")).In(s).Err()
+		assert.True(t, s.Failed())
+		assert.ErrorIs(t, s.Err(), errClosed)
+	})
+	t.Run("should return the values when there is no error", func(t *testing.T) {
+		s := &e.Scope{}
+		assert.Equal(t, 7, e.Try1(7, nil).In(s).ErrMessage("seven"))
+		s.Try(nil).Err()
+		assert.False(t, s.Failed())
+		assert.NoError(t, s.Err())
+	})
+	t.Run("should apply the handlers before recording", func(t *testing.T) {
+		s := &e.Scope{}
+		s.Try(errClosed).ErrMessage("read")
+		assert.EqualError(t, s.Err(), "read - error: closed")
+
+		s = &e.Scope{}
+		s.Try(errClosed).Err(func(error) error { return nil })
+		assert.False(t, s.Failed())
+	})
+	t.Run("should record the errors of Throw", func(t *testing.T) {
+		s := &e.Scope{}
+		s.Throw(nil)
+		assert.False(t, s.Failed())
+		s.Throw(errClosed, e.Message("close"))
+		s.Throw(errors.New("second"))
+		assert.EqualError(t, s.Err(), "close - error: closed")
+	})
+	t.Run("should record the errors Fallback does not handle", func(t *testing.T) {
+		s := &e.Scope{}
+		got := e.Try1(0, errClosed).In(s).IfIs(errClosed).Fallback(func(error) int { return 3 })
+		assert.Equal(t, 3, got)
+		assert.False(t, s.Failed())
+		got = e.Try1(0, errClosed).In(s).IfNot(errClosed).Fallback(func(error) int { return 3 })
+		assert.Zero(t, got)
+		assert.ErrorIs(t, s.Err(), errClosed)
+	})
+	t.Run("should set the named error in Handle", func(t *testing.T) {
+		sum, err := parsePair(&e.Scope{}, "1", "x")
+		assert.Equal(t, 1, sum)
+		assert.EqualError(t, err, `parse b - error: strconv.Atoi: parsing "x": invalid syntax`)
+
+		sum, err = parsePair(&e.Scope{}, "1", "2")
+		require.NoError(t, err)
+		assert.Equal(t, 3, sum)
+	})
+	t.Run("should throw in a nil scope", func(t *testing.T) {
+		var s *e.Scope
+		err := errlesstest.CaptureThrow(func() { s.Try(errClosed).ErrMessage("read") })
+		assert.EqualError(t, err, "read - error: closed")
+		assert.NoError(t, s.Err())
+
+		errlesstest.AssertThrowsIs(t, errClosed, func() { s.Throw(errClosed) })
+
+		_, err = parsePair(s, "1", "x")
+		assert.EqualError(t, err, `parse b - error: strconv.Atoi: parsing "x": invalid syntax`)
+	})
+}
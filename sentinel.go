@@ -0,0 +1,94 @@
+package errless
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var sentinels = struct {
+	mu     sync.RWMutex
+	byName map[string]error
+	names  map[error]string
+}{byName: map[string]error{}, names: map[error]string{}}
+
+// RegisterSentinel registers err under name, so errors that wrap it can be sent to another
+// process and still match it with errors.Is there. The name must be registered with an
+// equivalent sentinel on both sides. It returns err, so it can be used in a var declaration:
+//
+//	var ErrNotFound = errless.RegisterSentinel("account.not_found", errors.New("not found"))
+//
+// Like http.Handle, it panics if name is already registered for another error, or if err
+// is nil or not comparable.
+func RegisterSentinel(name string, err error) error {
+	invalid := fmt.Sprintf("errless: sentinel %q must be a comparable, non nil error", name)
+	if err == nil || !reflect.TypeOf(err).Comparable() {
+		panic(invalid)
+	}
+	sentinels.mu.Lock()
+	defer sentinels.mu.Unlock()
+	if _, hashable := lookupName(err); !hashable {
+		panic(invalid)
+	}
+	if registered, ok := sentinels.byName[name]; ok && registered != err {
+		panic(fmt.Sprintf("errless: sentinel %q is already registered", name))
+	}
+	sentinels.byName[name] = err
+	sentinels.names[err] = name
+	return err
+}
+
+// Sentinel returns the sentinel registered under name.
+func Sentinel(name string) (error, bool) {
+	sentinels.mu.RLock()
+	defer sentinels.mu.RUnlock()
+	err, ok := sentinels.byName[name]
+	return err, ok
+}
+
+// SentinelName returns the name of the first registered sentinel in the chain of err,
+// including the sentinels of errors rebuilt by FromSentinel and FromJSON.
+func SentinelName(err error) (string, bool) {
+	var name string
+	walk(err, func(err error) bool {
+		name = sentinelNameOf(err)
+		return name != ""
+	})
+	return name, name != ""
+}
+
+// FromSentinel rebuilds an error sent with the sentinel name, such as the value returned by
+// SentinelName. The error has message as its text, and matches the sentinel registered under
+// name with errors.Is.
+func FromSentinel(name, message string) error {
+	return &remoteError{remote: remote{message: message, sentinel: name}}
+}
+
+// sentinelNameOf returns the sentinel name of err itself, without following its chain.
+func sentinelNameOf(err error) string {
+	switch v := err.(type) {
+	case *remoteError:
+		return v.sentinel
+	case *remoteJoinError:
+		return v.sentinel
+	}
+	if !reflect.TypeOf(err).Comparable() {
+		return ""
+	}
+	sentinels.mu.RLock()
+	defer sentinels.mu.RUnlock()
+	name, _ := lookupName(err)
+	return name
+}
+
+// lookupName returns the name registered for err, and false if err cannot be hashed: a
+// comparable type can still hold an uncomparable value in an interface field, which makes
+// hashing it panic. The caller holds sentinels.mu.
+func lookupName(err error) (name string, hashable bool) {
+	defer func() {
+		if recover() != nil {
+			hashable = false
+		}
+	}()
+	return sentinels.names[err], true
+}
@@ -0,0 +1,99 @@
+//go:build test
+
+package errless_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	e "github.com/mfatihercik/errless"
+	"github.com/mfatihercik/errless/errlesstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errAccountNotFound = e.RegisterSentinel("errless_test.account_not_found", errors.New("account not found"))
+
+// opError is comparable, but cannot be hashed when Err holds an uncomparable error.
+type opError struct {
+	Op  string
+	Err error
+}
+
+func (e opError) Error() string { return e.Op + ": " + e.Err.Error() }
+func (e opError) Unwrap() error { return e.Err }
+
+func TestRegisterSentinel(t *testing.T) {
+	t.Run("should look up sentinels by name", func(t *testing.T) {
+		sentinel, ok := e.Sentinel("errless_test.account_not_found")
+		assert.True(t, ok)
+		assert.Same(t, errAccountNotFound, sentinel)
+		_, ok = e.Sentinel("errless_test.unknown")
+		assert.False(t, ok)
+	})
+	t.Run("should allow registering the same sentinel again", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			e.RegisterSentinel("errless_test.account_not_found", errAccountNotFound)
+		})
+	})
+	t.Run("should panic for another error under the same name", func(t *testing.T) {
+		assert.Panics(t, func() {
+			e.RegisterSentinel("errless_test.account_not_found", errors.New("account not found"))
+		})
+	})
+	t.Run("should panic for errors that are not comparable", func(t *testing.T) {
+		assert.Panics(t, func() { e.RegisterSentinel("errless_test.multi", multiError{errDB}) })
+		assert.Panics(t, func() { e.RegisterSentinel("errless_test.nil", nil) })
+		assert.PanicsWithValue(t, `errless: sentinel "errless_test.op" must be a comparable, non nil error`, func() {
+			e.RegisterSentinel("errless_test.op", opError{Op: "read", Err: multiError{errDB}})
+		})
+	})
+}
+
+func TestSentinelName(t *testing.T) {
+	name, ok := e.SentinelName(fmt.Errorf("load: %w", errAccountNotFound))
+	assert.True(t, ok)
+	assert.Equal(t, "errless_test.account_not_found", name)
+	_, ok = e.SentinelName(multiError{errDB, errors.New("other")})
+	assert.False(t, ok)
+	name, ok = e.SentinelName(opError{Op: "read", Err: multiError{errAccountNotFound}})
+	assert.True(t, ok)
+	assert.Equal(t, "errless_test.account_not_found", name)
+}
+
+func TestFromSentinel(t *testing.T) {
+	t.Run("should match the local sentinel", func(t *testing.T) {
+		err := e.FromSentinel("errless_test.account_not_found", "account 42 not found")
+		assert.EqualError(t, err, "account 42 not found")
+		assert.ErrorIs(t, err, errAccountNotFound)
+		assert.NotErrorIs(t, err, errDB)
+		name, _ := e.SentinelName(err)
+		assert.Equal(t, "errless_test.account_not_found", name)
+	})
+	t.Run("should not match unknown sentinels", func(t *testing.T) {
+		assert.NotErrorIs(t, e.FromSentinel("errless_test.unknown", "unknown"), errAccountNotFound)
+	})
+	t.Run("should work with If filters", func(t *testing.T) {
+		remote := e.FromSentinel("errless_test.account_not_found", "account not found")
+		errlesstest.AssertNoThrow(t, func() {
+			e.Try(remote).If(e.IsNot(errAccountNotFound)).Err()
+		})
+		errlesstest.AssertThrowsIs(t, errAccountNotFound, func() {
+			e.Try(remote).If(e.Is(errAccountNotFound)).Err()
+		})
+	})
+}
+
+func TestSentinelJSON(t *testing.T) {
+	err := roundTrip(t, e.Public("not found")(fmt.Errorf("load: %w", errAccountNotFound)))
+	assert.ErrorIs(t, err, errAccountNotFound)
+	assert.Equal(t, "not found", e.PublicMessageOf(err))
+
+	data, encodeErr := e.ToJSON(errAccountNotFound)
+	require.NoError(t, encodeErr)
+	assert.Contains(t, string(data), `"sentinel":"errless_test.account_not_found"`)
+
+	err = roundTrip(t, opError{Op: "read", Err: multiError{errAccountNotFound}})
+	assert.ErrorIs(t, err, errAccountNotFound)
+}
@@ -0,0 +1,59 @@
+package errless
+
+import (
+	"errors"
+	"iter"
+)
+
+// Seq returns the values of seq. It throws the first error seq yields, after applying the
+// handlers to it, from the range loop over the returned sequence. If the handlers return
+// nil for an error, its value is skipped and the loop goes on.
+//
+//	for row := range errless.Seq(rows.All(), errless.Message("scan")) {
+//		...
+//	}
+func Seq[T any](seq iter.Seq2[T, error], handles ...HandlerFunc) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v, err := range seq {
+			if err != nil {
+				Throw(err, handles...)
+				continue
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// SkipErrors is like Seq, but drops the values whose error matches skip instead of throwing.
+func SkipErrors[T any](seq iter.Seq2[T, error], skip IfFunc, handles ...HandlerFunc) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v, err := range seq {
+			if err != nil {
+				if !skip(err) {
+					Throw(err, handles...)
+				}
+				continue
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// CollectSeq returns the values seq yields without an error, and the errors it yields
+// joined with errors.Join.
+func CollectSeq[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var values []T
+	var errs []error
+	for v, err := range seq {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		values = append(values, v)
+	}
+	return values, errors.Join(errs...)
+}
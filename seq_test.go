@@ -0,0 +1,93 @@
+//go:build test
+
+package errless_test
+
+import (
+	"errors"
+	"iter"
+	"testing"
+
+	e "github.com/mfatihercik/errless"
+	"github.com/mfatihercik/errless/errlesstest"
+	"github.com/stretchr/testify/assert"
+)
+
+var errRow = errors.New("bad row")
+
+// rows yields the values, and errRow in place of the negative ones.
+func rows(values ...int) iter.Seq2[int, error] {
+	return func(yield func(int, error) bool) {
+		for _, v := range values {
+			var err error
+			if v < 0 {
+				err = errRow
+			}
+			if !yield(v, err) {
+				return
+			}
+		}
+	}
+}
+
+func TestSeq(t *testing.T) {
+	t.Run("should yield the values", func(t *testing.T) {
+		var got []int
+		for v := range e.Seq(rows(1, 2, 3)) {
+			got = append(got, v)
+		}
+		assert.Equal(t, []int{1, 2, 3}, got)
+	})
+	t.Run("should throw the first error", func(t *testing.T) {
+		var got []int
+		err := errlesstest.CaptureThrow(func() {
+			for v := range e.Seq(rows(1, -1, 3), e.Message("scan")) {
+				got = append(got, v)
+			}
+		})
+		assert.EqualError(t, err, "scan - error: bad row")
+		assert.Equal(t, []int{1}, got)
+	})
+	t.Run("should skip values whose error is handled", func(t *testing.T) {
+		var got []int
+		for v := range e.Seq(rows(1, -1, 3), func(error) error { return nil }) {
+			got = append(got, v)
+		}
+		assert.Equal(t, []int{1, 3}, got)
+	})
+	t.Run("should stop with the loop", func(t *testing.T) {
+		errlesstest.AssertNoThrow(t, func() {
+			for v := range e.Seq(rows(1, -1)) {
+				if v == 1 {
+					break
+				}
+			}
+		})
+	})
+}
+
+func TestSkipErrors(t *testing.T) {
+	t.Run("should drop matching errors", func(t *testing.T) {
+		var got []int
+		for v := range e.SkipErrors(rows(1, -1, 3), e.Is(errRow)) {
+			got = append(got, v)
+		}
+		assert.Equal(t, []int{1, 3}, got)
+	})
+	t.Run("should throw other errors", func(t *testing.T) {
+		errlesstest.AssertThrowsIs(t, errRow, func() {
+			for range e.SkipErrors(rows(1, -1, 3), e.Is(errDB)) {
+			}
+		})
+	})
+}
+
+func TestCollectSeq(t *testing.T) {
+	values, err := e.CollectSeq(rows(1, -1, 3, -2))
+	assert.Equal(t, []int{1, 3}, values)
+	assert.ErrorIs(t, err, errRow)
+	assert.Len(t, e.Flatten(err), 2)
+
+	values, err = e.CollectSeq(rows(1, 2))
+	assert.Equal(t, []int{1, 2}, values)
+	assert.NoError(t, err)
+}
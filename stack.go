@@ -0,0 +1,64 @@
+package errless
+
+import (
+	"errors"
+	"sync/atomic"
+
+	"github.com/mfatihercik/errless/internal/throw"
+)
+
+var stacksEnabled atomic.Bool
+
+// SetStacks turns capturing the stacks of thrown errors on or off for the whole process.
+// It returns a function that restores the previous setting.
+//
+// Walking the stack makes every throw slower, so it is meant for debugging. While it is on,
+// Throw wraps the errors it panics with in an error that carries the stack, like SetTrail,
+// and ToJSON encodes it.
+func SetStacks(enabled bool) (restore func()) {
+	previous := stacksEnabled.Swap(enabled)
+	return func() {
+		stacksEnabled.Store(previous)
+	}
+}
+
+// Frame is a function call in the stack of a thrown error.
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// Stack returns the stack err was first thrown with, starting at the site of the Throw,
+// or nil if it was thrown while stacks were not captured.
+func Stack(err error) []Frame {
+	var se *stackError
+	if errors.As(err, &se) {
+		return se.frames
+	}
+	return nil
+}
+
+// stackError carries the stack of the error it wraps.
+type stackError struct {
+	err    error
+	frames []Frame
+}
+
+func (e *stackError) Error() string                { return e.err.Error() }
+func (e *stackError) Unwrap() error                { return e.err }
+func (e *stackError) MarshalJSON() ([]byte, error) { return ToJSON(e) }
+
+// attachStack returns err carrying the frames of stack, unless it carries a stack already
+// because it is thrown again.
+func attachStack(err error, stack []uintptr) error {
+	if !stacksEnabled.Load() || stack == nil || Stack(err) != nil {
+		return err
+	}
+	sites := throw.Exception{Stack: stack}.Frames()
+	frames := make([]Frame, len(sites))
+	for i, site := range sites {
+		frames[i] = Frame{Function: site.Function, File: site.File, Line: site.Line}
+	}
+	return &stackError{err: err, frames: frames}
+}
@@ -0,0 +1,53 @@
+//go:build test
+
+package errless_test
+
+import (
+	"errors"
+	"testing"
+
+	e "github.com/mfatihercik/errless"
+	"github.com/mfatihercik/errless/errlesstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errStack = errors.New("stack error")
+
+func throwWithStack() {
+	e.Try(errStack).Err(prefix("load"))
+}
+
+func TestStack(t *testing.T) {
+	t.Run("should carry the stack of the throw", func(t *testing.T) {
+		defer e.SetStacks(true)()
+		err := errlesstest.CaptureThrow(throwWithStack)
+		stack := e.Stack(err)
+		require.NotEmpty(t, stack)
+		assert.Equal(t, "github.com/mfatihercik/errless_test.throwWithStack", stack[0].Function)
+		assert.Contains(t, stack[0].File, "stack_test.go")
+		assert.ErrorIs(t, err, errStack)
+		assert.EqualError(t, err, "load: stack error")
+	})
+	t.Run("should keep the first stack when thrown again", func(t *testing.T) {
+		defer e.SetStacks(true)()
+		err := errlesstest.CaptureThrow(throwWithStack)
+		again := errlesstest.CaptureThrow(func() { e.Rethrow(err) })
+		assert.Equal(t, e.Stack(err), e.Stack(again))
+	})
+	t.Run("should not capture stacks by default", func(t *testing.T) {
+		err := errlesstest.CaptureThrow(func() { e.Throw(errStack) })
+		assert.Nil(t, e.Stack(err))
+		assert.Same(t, errStack, err)
+	})
+	t.Run("should encode the stack frames", func(t *testing.T) {
+		defer e.SetStacks(true)()
+		err := errlesstest.CaptureThrow(throwWithStack)
+		data, encodeErr := e.ToJSON(err)
+		require.NoError(t, encodeErr)
+		assert.Contains(t, string(data), `"stack":[{"function":"github.com/mfatihercik/errless_test.throwWithStack"`)
+		decoded := roundTrip(t, err)
+		assert.Equal(t, e.Stack(err), e.Stack(decoded))
+		assert.EqualError(t, decoded, err.Error())
+	})
+}
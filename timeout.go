@@ -0,0 +1,84 @@
+package errless
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mfatihercik/errless/internal/throw"
+)
+
+// TimeoutError is the error of a call that did not return before its deadline.
+// It matches context.DeadlineExceeded with errors.Is.
+type TimeoutError struct {
+	Duration time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("errless: call did not return within %s", e.Duration)
+}
+
+// Timeout reports that the error is a timeout, like the errors of the net package.
+func (e *TimeoutError) Timeout() bool { return true }
+
+func (e *TimeoutError) Is(target error) bool { return target == context.DeadlineExceeded }
+
+// WithTimeout calls fn on a new goroutine and waits at most d for it to return. Its result
+// is checked like the result of Try, with a *TimeoutError if fn did not return in time.
+//
+// fn is not stopped when the deadline passes; it goes on in the background and its result is
+// dropped. An error thrown by fn is checked like the error fn returns, but the registered
+// handlers are not applied to it again. Any other panic in fn is raised again in the caller,
+// unless the deadline passed first.
+func WithTimeout(d time.Duration, fn func() error) Params0 {
+	r := withTimeout(d, func() (struct{}, error) {
+		return struct{}{}, fn()
+	})
+	if r.thrown {
+		return Params0{err: r.err, thrown: true}
+	}
+	return Try(r.err)
+}
+
+// WithTimeout1 is WithTimeout for a function that returns a value.
+func WithTimeout1[A any](d time.Duration, fn func() (A, error)) Params1[A] {
+	r := withTimeout(d, fn)
+	if r.thrown {
+		return Params1[A]{err: r.err, thrown: true}
+	}
+	return Try1(r.a, r.err)
+}
+
+type timeoutResult[A any] struct {
+	a      A
+	err    error
+	thrown bool
+	panic  any // a panic that was not raised by errless
+}
+
+func withTimeout[A any](d time.Duration, fn func() (A, error)) timeoutResult[A] {
+	done := make(chan timeoutResult[A], 1)
+	go func() {
+		var r timeoutResult[A]
+		defer func() {
+			switch p := recover().(type) {
+			case nil:
+			case throw.Exception:
+				r.err, r.thrown = p.Err, true
+			default:
+				r.panic = p
+			}
+			done <- r
+		}()
+		r.a, r.err = fn()
+	}()
+	select {
+	case r := <-done:
+		if r.panic != nil {
+			panic(r.panic)
+		}
+		return r
+	case <-clock().After(d):
+		return timeoutResult[A]{err: &TimeoutError{Duration: d}}
+	}
+}
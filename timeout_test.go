@@ -0,0 +1,109 @@
+//go:build test
+
+package errless_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	e "github.com/mfatihercik/errless"
+	"github.com/mfatihercik/errless/errlesstest"
+	"github.com/stretchr/testify/assert"
+)
+
+var errSlow = errors.New("slow call failed")
+
+func TestWithTimeout1(t *testing.T) {
+	t.Run("should return the result of a call in time", func(t *testing.T) {
+		errlesstest.UseFakeClock(t)
+		v := e.WithTimeout1(time.Second, func() (int, error) { return 42, nil }).Err()
+		assert.Equal(t, 42, v)
+	})
+	t.Run("should check the error of a call in time", func(t *testing.T) {
+		errlesstest.UseFakeClock(t)
+		err := errlesstest.CaptureThrow(func() {
+			e.WithTimeout1(time.Second, func() (int, error) { return 0, errSlow }).ErrMessage("call")
+		})
+		assert.EqualError(t, err, "call - error: slow call failed")
+	})
+	t.Run("should return the error thrown by the call", func(t *testing.T) {
+		errlesstest.UseFakeClock(t)
+		v := e.WithTimeout1(time.Second, func() (int, error) {
+			e.Throw(errSlow)
+			return 1, nil
+		}).Fallback(func(err error) int {
+			assert.ErrorIs(t, err, errSlow)
+			return 2
+		})
+		assert.Equal(t, 2, v)
+	})
+	t.Run("should apply the default handlers once to the error thrown by the call", func(t *testing.T) {
+		errlesstest.UseFakeClock(t)
+		errlesstest.DefaultHandlers(t, prefix("svc"))
+		err := errlesstest.CaptureThrow(func() {
+			e.WithTimeout(time.Second, func() error {
+				e.Throw(errSlow)
+				return nil
+			}).ErrMessage("call")
+		})
+		assert.EqualError(t, err, "call - error: svc: slow call failed")
+		err = errlesstest.CaptureThrow(func() {
+			e.WithTimeout1(time.Second, func() (int, error) { return 0, errSlow }).Err()
+		})
+		assert.EqualError(t, err, "svc: slow call failed")
+	})
+	t.Run("should repanic other panics in the caller", func(t *testing.T) {
+		errlesstest.UseFakeClock(t)
+		assert.PanicsWithValue(t, "boom", func() {
+			e.WithTimeout(time.Second, func() error { panic("boom") })
+		})
+	})
+	t.Run("should throw a timeout error when the deadline passes", func(t *testing.T) {
+		clock := errlesstest.UseFakeClock(t)
+		release := make(chan struct{})
+		defer close(release)
+		go func() {
+			clock.BlockUntil(1)
+			clock.Advance(time.Second)
+		}()
+		err := errlesstest.CaptureThrow(func() {
+			e.WithTimeout1(time.Second, func() (int, error) {
+				<-release
+				return 1, nil
+			}).Err()
+		})
+		var timeout *e.TimeoutError
+		assert.ErrorAs(t, err, &timeout)
+		assert.Equal(t, time.Second, timeout.Duration)
+		assert.True(t, timeout.Timeout())
+		assert.True(t, e.IsTimeout(err))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+	t.Run("should filter timeouts with If", func(t *testing.T) {
+		clock := errlesstest.UseFakeClock(t)
+		release := make(chan struct{})
+		defer close(release)
+		go func() {
+			clock.BlockUntil(1)
+			clock.Advance(2 * time.Second)
+		}()
+		errlesstest.AssertNoThrow(t, func() {
+			e.WithTimeout1(time.Second, func() (int, error) {
+				<-release
+				return 1, nil
+			}).IfNot(context.DeadlineExceeded).Err()
+		})
+	})
+}
+
+func TestWithTimeout(t *testing.T) {
+	errlesstest.UseFakeClock(t)
+	errlesstest.AssertThrowsIs(t, errSlow, func() {
+		e.WithTimeout(time.Second, func() error { return errSlow }).Err()
+	})
+	errlesstest.AssertNoThrow(t, func() {
+		e.WithTimeout(time.Second, func() error { return nil }).Err()
+	})
+}
@@ -0,0 +1,83 @@
+package errless
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// StatusCode is the status of a span. The values match the OpenTelemetry status codes.
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = iota
+	StatusError
+	StatusOK
+)
+
+// SpanRecorder is the part of a tracing span errless reports errors to.
+type SpanRecorder interface {
+	// RecordError adds an exception event for err to the span.
+	RecordError(err error)
+	// SetStatus sets the status of the span.
+	SetStatus(code StatusCode, description string)
+}
+
+// Tracer finds the active span of a context.
+type Tracer interface {
+	// SpanFromContext returns the span of ctx, or nil if ctx has no span that records.
+	SpanFromContext(ctx context.Context) SpanRecorder
+}
+
+type tracerHolder struct {
+	tracer Tracer
+}
+
+var tracer atomic.Pointer[tracerHolder]
+
+// SetTracer sets the tracer used by RecordSpan and HandleCtx for the whole process.
+// It returns a function that restores the previous tracer.
+func SetTracer(t Tracer) (restore func()) {
+	previous := tracer.Swap(&tracerHolder{tracer: t})
+	return func() {
+		tracer.Store(previous)
+	}
+}
+
+// RecordSpan returns a handler that records the error on the span of ctx and marks the
+// span as failed. The error is passed on unchanged.
+func RecordSpan(ctx context.Context) HandlerFunc {
+	return func(err error) error {
+		recordSpan(ctx, err)
+		return err
+	}
+}
+
+// HandleCtx is used like Handle, and records the error returned by onError on the span
+// of ctx. A nil onError returns the caught error unchanged.
+func HandleCtx(ctx context.Context, namedErr *error, onError func(error) error) {
+	exp := recoverException(recover())
+	if exp != nil {
+		t := recoverTrail(exp, "errless.HandleCtx")
+		err := exp.Err
+		if onError != nil {
+			t.handled(onError)
+			err = t.attach(onError(err))
+		}
+		observeRecover(exp, err)
+		recordSpan(ctx, err)
+		if namedErr != nil {
+			*namedErr = err
+		}
+	}
+}
+
+func recordSpan(ctx context.Context, err error) {
+	h := tracer.Load()
+	if err == nil || h == nil || h.tracer == nil || ctx == nil {
+		return
+	}
+	if span := h.tracer.SpanFromContext(ctx); span != nil {
+		span.RecordError(err)
+		span.SetStatus(StatusError, err.Error())
+	}
+}
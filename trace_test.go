@@ -0,0 +1,65 @@
+//go:build test
+
+package errless_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	e "github.com/mfatihercik/errless"
+	"github.com/mfatihercik/errless/errlesstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errSpan = errors.New("span error")
+
+func TestRecordSpan(t *testing.T) {
+	t.Run("should record the error on the span of the context", func(t *testing.T) {
+		ctx, span := errlesstest.StartSpan(t, context.Background())
+		err := errlesstest.CaptureThrow(func() {
+			e.Try(errSpan).Err(e.RecordSpan(ctx))
+		})
+		assert.ErrorIs(t, err, errSpan)
+		assert.Equal(t, []error{errSpan}, span.Errors())
+		code, description := span.Status()
+		assert.Equal(t, e.StatusError, code)
+		assert.Equal(t, "span error", description)
+	})
+	t.Run("should ignore contexts without a span", func(t *testing.T) {
+		errlesstest.StartSpan(t, context.Background())
+		err := errlesstest.CaptureThrow(func() {
+			e.Try(errSpan).Err(e.RecordSpan(context.Background()))
+		})
+		assert.ErrorIs(t, err, errSpan)
+	})
+}
+
+func TestHandleCtx(t *testing.T) {
+	load := func(ctx context.Context, onError func(error) error) (err error) {
+		defer e.HandleCtx(ctx, &err, onError)
+		e.Try(errSpan).Err()
+		return nil
+	}
+	t.Run("should record the handled error", func(t *testing.T) {
+		ctx, span := errlesstest.StartSpan(t, context.Background())
+		err := load(ctx, func(err error) error { return fmt.Errorf("load: %w", err) })
+		assert.EqualError(t, err, "load: span error")
+		require.Len(t, span.Errors(), 1)
+		assert.ErrorIs(t, span.Errors()[0], errSpan)
+	})
+	t.Run("should return the caught error without a handler", func(t *testing.T) {
+		ctx, span := errlesstest.StartSpan(t, context.Background())
+		assert.ErrorIs(t, load(ctx, nil), errSpan)
+		assert.Len(t, span.Errors(), 1)
+	})
+	t.Run("should not record a swallowed error", func(t *testing.T) {
+		ctx, span := errlesstest.StartSpan(t, context.Background())
+		assert.NoError(t, load(ctx, func(error) error { return nil }))
+		assert.Empty(t, span.Errors())
+		code, _ := span.Status()
+		assert.Equal(t, e.StatusUnset, code)
+	})
+}
@@ -0,0 +1,176 @@
+package errless
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync/atomic"
+
+	"github.com/mfatihercik/errless/internal/throw"
+)
+
+var trailEnabled atomic.Bool
+
+// SetTrail turns recording of error trails on or off for the whole process.
+// It returns a function that restores the previous setting.
+//
+// While recording is on, Throw wraps the errors it panics with in an error that carries
+// the trail, so errors.Is and errors.As keep working but a type assertion on the thrown
+// error itself does not.
+func SetTrail(enabled bool) (restore func()) {
+	previous := trailEnabled.Swap(enabled)
+	return func() {
+		trailEnabled.Store(previous)
+	}
+}
+
+// BreadcrumbKind tells what a Breadcrumb records.
+type BreadcrumbKind int
+
+const (
+	// Thrown is a Throw, or a TryN check, that panicked with the error.
+	Thrown BreadcrumbKind = iota
+	// Handled is a handler applied to the error.
+	Handled
+	// Recovered is a Handle, HandleErr or Catch that recovered the error.
+	Recovered
+)
+
+// Breadcrumb is a step of the trail of an error.
+type Breadcrumb struct {
+	Kind BreadcrumbKind `json:"kind"`
+	// Name is the name of the handler, or of the errless function that recovered the error.
+	Name string `json:"name,omitempty"`
+	// Function and Site are the function and the "file:line" position of a throw or a recover.
+	Function string `json:"function,omitempty"`
+	Site     string `json:"site,omitempty"`
+}
+
+var breadcrumbKinds = [...]string{Thrown: "thrown", Handled: "handled", Recovered: "recovered"}
+
+func (k BreadcrumbKind) String() string {
+	if k >= 0 && int(k) < len(breadcrumbKinds) {
+		return breadcrumbKinds[k]
+	}
+	return "unknown"
+}
+
+// MarshalText writes the kind by name, for the JSON form of errors.
+func (k BreadcrumbKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText reads a kind written by MarshalText.
+func (k *BreadcrumbKind) UnmarshalText(text []byte) error {
+	for kind, name := range breadcrumbKinds {
+		if name == string(text) {
+			*k = BreadcrumbKind(kind)
+			return nil
+		}
+	}
+	return fmt.Errorf("errless: unknown breadcrumb kind %q", text)
+}
+
+// Breadcrumbs is the record of the throws, handlers and recovers an error went through, oldest first.
+type Breadcrumbs []Breadcrumb
+
+// String prints the breadcrumbs as a tree, with the handlers under the throw or recover that applied them.
+func (t Breadcrumbs) String() string {
+	var b strings.Builder
+	for i, step := range t {
+		switch step.Kind {
+		case Handled:
+			if i+1 < len(t) && t[i+1].Kind == Handled {
+				b.WriteString("├─ ")
+			} else {
+				b.WriteString("└─ ")
+			}
+			b.WriteString(step.Name)
+		case Thrown:
+			b.WriteString("thrown in " + step.Function + " at " + step.Site)
+		case Recovered:
+			b.WriteString("recovered by " + step.Name + " in " + step.Function + " at " + step.Site)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Trail returns the trail recorded for err, or nil if err was not thrown while recording was on.
+func Trail(err error) Breadcrumbs {
+	var te *trailError
+	if errors.As(err, &te) {
+		return te.trail
+	}
+	return nil
+}
+
+// trailError carries the trail of the error it wraps.
+type trailError struct {
+	err   error
+	trail Breadcrumbs
+}
+
+func (e *trailError) Error() string { return e.err.Error() }
+func (e *trailError) Unwrap() error { return e.err }
+
+// trailRecorder collects the steps of a single throw or recover.
+// A nil recorder records nothing, so callers do not need to check whether recording is on.
+type trailRecorder struct {
+	trail Breadcrumbs
+	start int // index of the throw or recover step
+}
+
+// startTrail starts recording step for err, or returns nil if recording is off.
+func startTrail(err error, step Breadcrumb) *trailRecorder {
+	if !trailEnabled.Load() {
+		return nil
+	}
+	previous := Trail(err)
+	trail := make(Breadcrumbs, len(previous), len(previous)+4)
+	copy(trail, previous)
+	return &trailRecorder{trail: append(trail, step), start: len(previous)}
+}
+
+// recoverTrail records that the errless function name recovered the error of exp.
+func recoverTrail(exp *throw.Exception, name string) *trailRecorder {
+	t := startTrail(exp.Err, Breadcrumb{Kind: Recovered, Name: name})
+	if t != nil {
+		t.at(throw.Caller())
+		exp.Err = t.attach(exp.Err)
+	}
+	return t
+}
+
+// at sets the position of the throw or recover step.
+func (t *trailRecorder) at(site throw.Site) {
+	if t != nil {
+		t.trail[t.start].Function = site.Function
+		t.trail[t.start].Site = site.String()
+	}
+}
+
+// handled records the handler fn, a func value.
+func (t *trailRecorder) handled(fn any) {
+	if t == nil {
+		return
+	}
+	name := "<unknown>"
+	if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+		name = f.Name()
+	}
+	t.trail = append(t.trail, Breadcrumb{Kind: Handled, Name: name})
+}
+
+// attach returns err carrying the recorded trail.
+func (t *trailRecorder) attach(err error) error {
+	if t == nil || err == nil {
+		return err
+	}
+	if te, ok := err.(*trailError); ok {
+		err = te.err
+	}
+	return &trailError{err: err, trail: t.trail}
+}
@@ -0,0 +1,84 @@
+//go:build test
+
+package errless_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	e "github.com/mfatihercik/errless"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errTrail = errors.New("trail error")
+
+func annotate(err error) error {
+	return fmt.Errorf("annotated: %w", err)
+}
+
+func loadWithTrail() (err error) {
+	defer e.Handle(&err, annotate)
+	e.Try(errTrail).Err(annotate, e.Message("load"))
+	return nil
+}
+
+func TestTrail(t *testing.T) {
+	t.Run("should record throws, handlers and recovers", func(t *testing.T) {
+		defer e.SetTrail(true)()
+		err := loadWithTrail()
+		trail := e.Trail(err)
+		require.Len(t, trail, 5)
+		assert.Equal(t, e.Thrown, trail[0].Kind)
+		assert.Equal(t, "github.com/mfatihercik/errless_test.loadWithTrail", trail[0].Function)
+		assert.Contains(t, trail[0].Site, "trail_test.go:")
+		assert.Equal(t, e.Handled, trail[1].Kind)
+		assert.Equal(t, "github.com/mfatihercik/errless_test.annotate", trail[1].Name)
+		assert.Contains(t, trail[2].Name, "errless.Message")
+		assert.Equal(t, e.Recovered, trail[3].Kind)
+		assert.Equal(t, "errless.Handle", trail[3].Name)
+		assert.Equal(t, "github.com/mfatihercik/errless_test.loadWithTrail", trail[3].Function)
+		assert.Equal(t, "github.com/mfatihercik/errless_test.annotate", trail[4].Name)
+		assert.EqualError(t, err, "annotated: load - error: annotated: trail error")
+	})
+	t.Run("should print the trail as a tree", func(t *testing.T) {
+		trail := e.Breadcrumbs{
+			{Kind: e.Thrown, Function: "app.load", Site: "app/load.go:12"},
+			{Kind: e.Handled, Name: "app.wrap"},
+			{Kind: e.Handled, Name: "app.log"},
+			{Kind: e.Recovered, Name: "errless.HandleErr", Function: "app.load", Site: "app/load.go:12"},
+		}
+		assert.Equal(t, "thrown in app.load at app/load.go:12\n"+
+			"├─ app.wrap\n"+
+			"└─ app.log\n"+
+			"recovered by errless.HandleErr in app.load at app/load.go:12\n", trail.String())
+	})
+	t.Run("should keep the trail across functions", func(t *testing.T) {
+		defer e.SetTrail(true)()
+		var caught error
+		func() {
+			defer e.Catch(func(err error) { caught = err })
+			e.Try(loadWithTrail()).ErrWrap("outer")
+		}()
+		trail := e.Trail(caught)
+		require.Len(t, trail, 8)
+		assert.Equal(t, e.Thrown, trail[5].Kind)
+		assert.Equal(t, e.Recovered, trail[7].Kind)
+		assert.Equal(t, "errless.Catch", trail[7].Name)
+	})
+	t.Run("should keep errors.Is working", func(t *testing.T) {
+		defer e.SetTrail(true)()
+		var err error
+		func() {
+			defer e.HandleErr(&err)
+			e.Try(errTrail).Err(annotate)
+		}()
+		assert.ErrorIs(t, err, errTrail)
+		assert.Len(t, e.Trail(err), 3)
+	})
+	t.Run("should not record when disabled", func(t *testing.T) {
+		err := loadWithTrail()
+		assert.Nil(t, e.Trail(err))
+	})
+}